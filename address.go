@@ -0,0 +1,144 @@
+package systemd
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// AddressFamily represents the kind of a socket unit's listening address, as derived from the address' syntax.
+type AddressFamily string
+
+const (
+	AddressPort     AddressFamily = "port"     // A port number only, e.g. "8080"; systemd listens on all IPv6 and, depending on BindIPv6Only=, IPv4 addresses.
+	AddressIPv4     AddressFamily = "ipv4"     // An IPv4 address and port, e.g. "127.0.0.1:8080".
+	AddressIPv6     AddressFamily = "ipv6"     // An IPv6 address and port, e.g. "[::1]:8080", optionally with an interface zone ("[fe80::1%eth0]:8080").
+	AddressUnix     AddressFamily = "unix"     // A file system AF_UNIX socket path, e.g. "/run/example.sock".
+	AddressAbstract AddressFamily = "abstract" // An abstract namespace AF_UNIX socket, e.g. "@example".
+	AddressVSock    AddressFamily = "vsock"    // An AF_VSOCK context identifier and port, e.g. "vsock:2:1234"; the context identifier may be omitted.
+)
+
+// Address represents a single listening address of a ListenStream=, ListenDatagram= or ListenSequentialPacket= directive.
+//
+// Example:
+//
+//	Address{Family: AddressIPv6, Host: "::1", Port: 8080} // ListenStream=[::1]:8080
+//	Address{Family: AddressUnix, Path: "/run/example.sock"} // ListenStream=/run/example.sock
+//
+// See [systemd.socket] for additional details.
+//
+// [systemd.socket]: https://www.freedesktop.org/software/systemd/man/latest/systemd.socket.html#ListenStream=
+type Address struct {
+	Family AddressFamily `json:"Family" yaml:"Family"`                 // Specifies the kind of address.
+	Host   string        `json:"Host,omitempty" yaml:"Host,omitempty"` // Specifies the IP address of ipv4 and ipv6 addresses, or the context identifier of vsock addresses.
+	Port   uint32        `json:"Port,omitempty" yaml:"Port,omitempty"` // Specifies the port of port, ipv4, ipv6 and vsock addresses.
+	Path   string        `json:"Path,omitempty" yaml:"Path,omitempty"` // Specifies the socket path of unix addresses, or the name (without the "@" prefix) of abstract addresses.
+}
+
+// ParseAddress parses a socket unit's listening address; see [AddressFamily] for the supported forms.
+func ParseAddress(v string) (Address, error) {
+	v = strings.TrimSpace(v)
+
+	switch {
+	case v == "":
+		return Address{}, fmt.Errorf("empty socket address")
+	case strings.HasPrefix(v, "/"):
+		return Address{Family: AddressUnix, Path: v}, nil
+	case strings.HasPrefix(v, "@"):
+		if len(v) == 1 {
+			return Address{}, fmt.Errorf("empty abstract socket name")
+		}
+
+		return Address{Family: AddressAbstract, Path: v[1:]}, nil
+	case strings.HasPrefix(v, "vsock:"):
+		cid, port, valid := strings.Cut(strings.TrimPrefix(v, "vsock:"), ":")
+		if !(valid) {
+			return Address{}, fmt.Errorf("missing vsock port: %q", v)
+		}
+
+		if cid != "" {
+			if _, e := strconv.ParseUint(cid, 10, 32); e != nil {
+				return Address{}, fmt.Errorf("invalid vsock context identifier of %q: %w", v, e)
+			}
+		}
+
+		n, e := strconv.ParseUint(port, 10, 32)
+		if e != nil {
+			return Address{}, fmt.Errorf("invalid vsock port of %q: %w", v, e)
+		}
+
+		return Address{Family: AddressVSock, Host: cid, Port: uint32(n)}, nil
+	}
+
+	if n, e := strconv.ParseUint(v, 10, 16); e == nil {
+		if n == 0 {
+			return Address{}, fmt.Errorf("invalid port: %q", v)
+		}
+
+		return Address{Family: AddressPort, Port: uint32(n)}, nil
+	}
+
+	pair, e := netip.ParseAddrPort(v)
+	if e != nil {
+		return Address{}, fmt.Errorf("invalid socket address %q: %w", v, e)
+	}
+
+	if pair.Port() == 0 {
+		return Address{}, fmt.Errorf("invalid port: %q", v)
+	}
+
+	family := AddressIPv4
+	if pair.Addr().Is6() {
+		family = AddressIPv6
+	}
+
+	return Address{Family: family, Host: pair.Addr().String(), Port: uint32(pair.Port())}, nil
+}
+
+// String returns the Address in its directive value form.
+func (a Address) String() string {
+	port := strconv.FormatUint(uint64(a.Port), 10)
+
+	switch a.Family {
+	case AddressPort:
+		return port
+	case AddressIPv4:
+		return a.Host + ":" + port
+	case AddressIPv6:
+		return "[" + a.Host + "]:" + port
+	case AddressUnix:
+		return a.Path
+	case AddressAbstract:
+		return "@" + a.Path
+	case AddressVSock:
+		return "vsock:" + a.Host + ":" + port
+	}
+
+	return ""
+}
+
+func (a Address) MarshalText() ([]byte, error) {
+	v := a.String()
+	if v == "" {
+		return nil, fmt.Errorf("invalid socket address family: %q", a.Family)
+	}
+
+	// Round-trip the rendered value, validating the address' fields against its family.
+	if _, e := ParseAddress(v); e != nil {
+		return nil, e
+	}
+
+	return []byte(v), nil
+}
+
+func (a *Address) UnmarshalText(content []byte) error {
+	instance, e := ParseAddress(string(content))
+	if e != nil {
+		return e
+	}
+
+	*a = instance
+
+	return nil
+}
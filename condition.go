@@ -0,0 +1,145 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConditionType represents the kind of check performed by a Condition*= or Assert*= directive; e.g. "PathExists" for ConditionPathExists= and
+// AssertPathExists=.
+//
+// See [systemd.unit] for additional details.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Conditions%20and%20Asserts
+type ConditionType string
+
+const (
+	ConditionArchitecture           ConditionType = "Architecture"           // Checks whether the system is running on a specific architecture (e.g. x86-64, arm64).
+	ConditionFirmware               ConditionType = "Firmware"               // Checks whether the system's firmware is of a certain type (e.g. uefi, device-tree).
+	ConditionVirtualization         ConditionType = "Virtualization"         // Checks whether the system is executed in a virtualized environment, optionally of a specific technology.
+	ConditionHost                   ConditionType = "Host"                   // Checks whether the system's hostname or machine ID matches.
+	ConditionKernelCommandLine      ConditionType = "KernelCommandLine"      // Checks whether a specific kernel command line option is set (or, with "=", set to a specific value).
+	ConditionKernelVersion          ConditionType = "KernelVersion"          // Checks whether the kernel version (as reported by uname -r) matches a comparison expression.
+	ConditionVersion                ConditionType = "Version"                // Checks whether a component's version (the kernel, "systemd" or "glibc") matches a comparison expression.
+	ConditionKernelModuleLoaded     ConditionType = "KernelModuleLoaded"     // Checks whether the given kernel module is loaded.
+	ConditionCredential             ConditionType = "Credential"             // Checks whether the specified credential was passed to the service manager.
+	ConditionEnvironment            ConditionType = "Environment"            // Checks whether the manager's environment contains a variable, optionally with a specific value.
+	ConditionSecurity               ConditionType = "Security"               // Checks whether a security technology is enabled (e.g. selinux, apparmor, tpm2).
+	ConditionCapability             ConditionType = "Capability"             // Checks whether the given capability exists in the capability bounding set of the service manager.
+	ConditionACPower                ConditionType = "ACPower"                // Checks whether the system is connected to an external power source.
+	ConditionNeedsUpdate            ConditionType = "NeedsUpdate"            // Checks whether /etc or /var require an update after a /usr modification.
+	ConditionFirstBoot              ConditionType = "FirstBoot"              // Checks whether the system is booting up for the first time.
+	ConditionPathExists             ConditionType = "PathExists"             // Checks for the existence of a file.
+	ConditionPathExistsGlob         ConditionType = "PathExistsGlob"         // Checks for the existence of at least one file matching a glob pattern.
+	ConditionPathIsDirectory        ConditionType = "PathIsDirectory"        // Checks whether the path exists and is a directory.
+	ConditionPathIsSymbolicLink     ConditionType = "PathIsSymbolicLink"     // Checks whether the path exists and is a symbolic link.
+	ConditionPathIsMountPoint       ConditionType = "PathIsMountPoint"       // Checks whether the path exists and is a mount point.
+	ConditionPathIsReadWrite        ConditionType = "PathIsReadWrite"        // Checks whether the path exists and is on a writable file system.
+	ConditionPathIsEncrypted        ConditionType = "PathIsEncrypted"        // Checks whether the path exists and is on an encrypted block device.
+	ConditionDirectoryNotEmpty      ConditionType = "DirectoryNotEmpty"      // Checks whether the path exists and is a non-empty directory.
+	ConditionFileNotEmpty           ConditionType = "FileNotEmpty"           // Checks whether the path exists and refers to a regular file with a non-zero size.
+	ConditionFileIsExecutable       ConditionType = "FileIsExecutable"       // Checks whether the path exists and refers to a regular file marked executable.
+	ConditionUser                   ConditionType = "User"                   // Checks whether the service manager runs as the given user (name, UID, or "@system").
+	ConditionGroup                  ConditionType = "Group"                  // Checks whether the service manager runs as, or is a member of, the given group.
+	ConditionControlGroupController ConditionType = "ControlGroupController" // Checks whether the given cgroup controller(s) are available, or the cgroup hierarchy version ("v1", "v2").
+	ConditionMemory                 ConditionType = "Memory"                 // Checks whether the system's physical memory satisfies a comparison expression (e.g. ">= 4G").
+	ConditionCPUs                   ConditionType = "CPUs"                   // Checks whether the system's CPU count satisfies a comparison expression.
+	ConditionCPUFeature             ConditionType = "CPUFeature"             // Checks whether the given CPU feature is available (x86 only).
+	ConditionOSRelease              ConditionType = "OSRelease"              // Checks whether an os-release(5) key-value pair satisfies a comparison expression (e.g. "ID=fedora").
+	ConditionMemoryPressure         ConditionType = "MemoryPressure"         // Checks whether the memory pressure (PSI) is below the given threshold.
+	ConditionCPUPressure            ConditionType = "CPUPressure"            // Checks whether the CPU pressure (PSI) is below the given threshold.
+	ConditionIOPressure             ConditionType = "IOPressure"             // Checks whether the IO pressure (PSI) is below the given threshold.
+)
+
+// conditions represents the set of known condition types.
+var conditions = map[ConditionType]struct{}{
+	ConditionArchitecture: {}, ConditionFirmware: {}, ConditionVirtualization: {}, ConditionHost: {}, ConditionKernelCommandLine: {},
+	ConditionKernelVersion: {}, ConditionVersion: {}, ConditionKernelModuleLoaded: {}, ConditionCredential: {}, ConditionEnvironment: {},
+	ConditionSecurity: {}, ConditionCapability: {}, ConditionACPower: {}, ConditionNeedsUpdate: {}, ConditionFirstBoot: {},
+	ConditionPathExists: {}, ConditionPathExistsGlob: {}, ConditionPathIsDirectory: {}, ConditionPathIsSymbolicLink: {},
+	ConditionPathIsMountPoint: {}, ConditionPathIsReadWrite: {}, ConditionPathIsEncrypted: {}, ConditionDirectoryNotEmpty: {},
+	ConditionFileNotEmpty: {}, ConditionFileIsExecutable: {}, ConditionUser: {}, ConditionGroup: {}, ConditionControlGroupController: {},
+	ConditionMemory: {}, ConditionCPUs: {}, ConditionCPUFeature: {}, ConditionOSRelease: {}, ConditionMemoryPressure: {},
+	ConditionCPUPressure: {}, ConditionIOPressure: {},
+}
+
+// Valid reports whether the ConditionType is known.
+func (c ConditionType) Valid() bool {
+	_, ok := conditions[c]
+
+	return ok
+}
+
+// Condition represents a single Condition*= or Assert*= directive of the [Unit] section. Whether the Condition is written as a condition or as an
+// assertion depends on the [Unit] field it's assigned to ([Unit.Conditions] or [Unit.Asserts]).
+//
+//   - If Trigger is set, the value is prefixed with a pipe symbol ("|"), making it a triggering condition: the unit starts if at least one
+//     triggering condition applies and all regular (non-triggering) conditions apply.
+//   - If Negate is set, the value is prefixed with an exclamation mark ("!"), inverting the check.
+//
+// Example:
+//
+//	Condition{Type: ConditionPathExists, Value: "/etc/example", Negate: true} // ConditionPathExists=!/etc/example
+type Condition struct {
+	Type    ConditionType `json:"Type" yaml:"Type"`                           // Specifies the kind of check; the remainder of the directive's name.
+	Value   string        `json:"Value" yaml:"Value"`                         // Specifies the check's argument, without any of the negation or triggering prefixes.
+	Negate  bool          `json:"Negate,omitempty" yaml:"Negate,omitempty"`   // Inverts the check ("!").
+	Trigger bool          `json:"Trigger,omitempty" yaml:"Trigger,omitempty"` // Marks the check as a triggering condition ("|").
+}
+
+func (c Condition) suffix() string {
+	return string(c.Type)
+}
+
+func (c *Condition) assign(suffix string) error {
+	if !(ConditionType(suffix).Valid()) {
+		return fmt.Errorf("unknown condition type: %q", suffix)
+	}
+
+	c.Type = ConditionType(suffix)
+
+	return nil
+}
+
+// String returns the Condition's directive value, including any prefixes.
+func (c Condition) String() string {
+	var builder strings.Builder
+	if c.Trigger {
+		builder.WriteString("|")
+	}
+
+	if c.Negate {
+		builder.WriteString("!")
+	}
+
+	builder.WriteString(c.Value)
+
+	return builder.String()
+}
+
+func (c Condition) MarshalText() ([]byte, error) {
+	if !(c.Type.Valid()) {
+		return nil, fmt.Errorf("invalid condition type: %q", c.Type)
+	}
+
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText parses a directive value's triggering and negation prefixes. The pipe symbol must come first if both prefixes are used; whitespace
+// is allowed in between the prefixes and the value. The Condition's Type is left unmodified.
+func (c *Condition) UnmarshalText(content []byte) error {
+	v := strings.TrimSpace(string(content))
+
+	c.Trigger, c.Negate = false, false
+	if remainder, ok := strings.CutPrefix(v, "|"); ok {
+		c.Trigger, v = true, strings.TrimSpace(remainder)
+	}
+
+	if remainder, ok := strings.CutPrefix(v, "!"); ok {
+		c.Negate, v = true, strings.TrimSpace(remainder)
+	}
+
+	c.Value = v
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestCondition(t *testing.T) {
+	t.Run("Condition-Marshal-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Description: "Conditional Daemon",
+				Conditions: []systemd.Condition{
+					{Type: systemd.ConditionPathExists, Value: "/etc/example", Negate: true},
+					{Type: systemd.ConditionVirtualization, Value: "container", Trigger: true},
+					{Type: systemd.ConditionHost, Value: "example", Trigger: true, Negate: true},
+				},
+				Asserts: []systemd.Condition{
+					{Type: systemd.ConditionPathIsDirectory, Value: "/var/lib/example"},
+				},
+			},
+			Service: systemd.Service{
+				ExecStart: []string{"/usr/bin/example-agent"},
+			},
+		}
+
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectations := []string{
+			"ConditionPathExists=!/etc/example\n",
+			"ConditionVirtualization=|container\n",
+			"ConditionHost=|!example\n",
+			"AssertPathIsDirectory=/var/lib/example\n",
+		}
+
+		for _, expectation := range expectations {
+			if !(strings.Contains(string(content), expectation)) {
+				t.Errorf("expected %q in marshalled output:\n%s", expectation, string(content))
+			}
+		}
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Unit.Conditions, daemon.Unit.Conditions)) {
+			t.Errorf("unexpected conditions after round-trip: %+v", instance.Unit.Conditions)
+		}
+
+		if !(reflect.DeepEqual(instance.Unit.Asserts, daemon.Unit.Asserts)) {
+			t.Errorf("unexpected asserts after round-trip: %+v", instance.Unit.Asserts)
+		}
+	})
+
+	t.Run("Condition-Reset-Test", func(t *testing.T) {
+		content := []byte(strings.Join([]string{
+			"[Unit]",
+			"Description=Reset",
+			"ConditionPathExists=/etc/a",
+			"ConditionFirstBoot=yes",
+			"ConditionPathExists=",
+			"ConditionKernelCommandLine= | ! quiet",
+		}, "\n"))
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		expectation := []systemd.Condition{{Type: systemd.ConditionKernelCommandLine, Value: "quiet", Negate: true, Trigger: true}}
+		if !(reflect.DeepEqual(instance.Unit.Conditions, expectation)) {
+			t.Errorf("unexpected conditions: %+v", instance.Unit.Conditions)
+		}
+	})
+
+	t.Run("Condition-Type-Round-Trip-Test", func(t *testing.T) {
+		cases := map[systemd.ConditionType]string{
+			systemd.ConditionArchitecture:           "x86-64",
+			systemd.ConditionFirmware:               "uefi",
+			systemd.ConditionVirtualization:         "container",
+			systemd.ConditionHost:                   "example",
+			systemd.ConditionKernelCommandLine:      "quiet",
+			systemd.ConditionKernelVersion:          ">= 6.1",
+			systemd.ConditionVersion:                "systemd >= 254",
+			systemd.ConditionKernelModuleLoaded:     "wireguard",
+			systemd.ConditionCredential:             "token",
+			systemd.ConditionEnvironment:            "MODE=production",
+			systemd.ConditionSecurity:               "selinux",
+			systemd.ConditionCapability:             "CAP_NET_ADMIN",
+			systemd.ConditionACPower:                "true",
+			systemd.ConditionNeedsUpdate:            "/etc",
+			systemd.ConditionFirstBoot:              "yes",
+			systemd.ConditionPathExists:             "/etc/example",
+			systemd.ConditionPathExistsGlob:         "/etc/example/*.conf",
+			systemd.ConditionPathIsDirectory:        "/var/lib/example",
+			systemd.ConditionPathIsSymbolicLink:     "/etc/localtime",
+			systemd.ConditionPathIsMountPoint:       "/srv",
+			systemd.ConditionPathIsReadWrite:        "/var",
+			systemd.ConditionPathIsEncrypted:        "/home",
+			systemd.ConditionDirectoryNotEmpty:      "/etc/example.d",
+			systemd.ConditionFileNotEmpty:           "/etc/example.conf",
+			systemd.ConditionFileIsExecutable:       "/usr/bin/example",
+			systemd.ConditionUser:                   "@system",
+			systemd.ConditionGroup:                  "wheel",
+			systemd.ConditionControlGroupController: "v2",
+			systemd.ConditionMemory:                 ">= 4G",
+			systemd.ConditionCPUs:                   ">= 2",
+			systemd.ConditionCPUFeature:             "rdrand",
+			systemd.ConditionOSRelease:              "ID=fedora",
+			systemd.ConditionMemoryPressure:         "20%",
+			systemd.ConditionCPUPressure:            "20%",
+			systemd.ConditionIOPressure:             "20%",
+		}
+
+		for kind, value := range cases {
+			for _, prefix := range []string{"Condition", "Assert"} {
+				if _, ok := systemd.Lookup("Unit", prefix+string(kind)); !(ok) {
+					t.Errorf("expected %s%s= to be registered", prefix, kind)
+				}
+			}
+
+			daemon := systemd.Daemon{
+				Unit: systemd.Unit{
+					Conditions: []systemd.Condition{{Type: kind, Value: value}},
+					Asserts:    []systemd.Condition{{Type: kind, Value: value, Negate: true}},
+				},
+			}
+
+			content, e := systemd.Marshal(daemon)
+			if e != nil {
+				t.Errorf("failed marshalling %s: %v", kind, e)
+				continue
+			}
+
+			instance, e := systemd.Unmarshal(content)
+			if e != nil {
+				t.Errorf("failed unmarshalling %s: %v", kind, e)
+				continue
+			}
+
+			if !(reflect.DeepEqual(instance.Unit.Conditions, daemon.Unit.Conditions)) || !(reflect.DeepEqual(instance.Unit.Asserts, daemon.Unit.Asserts)) {
+				t.Errorf("unexpected %s round-trip: %+v, %+v", kind, instance.Unit.Conditions, instance.Unit.Asserts)
+			}
+		}
+	})
+
+	t.Run("Condition-Unknown-Type-Test", func(t *testing.T) {
+		for _, directive := range []string{"ConditionUnknownCheck=x", "AssertPathExsts=/etc/example"} {
+			_, e := systemd.Unmarshal([]byte("[Unit]\nDescription=Unknown\n" + directive + "\n"))
+			if e == nil || !(strings.Contains(e.Error(), "unknown condition type")) {
+				t.Errorf("expected an unknown condition type error for %s, got: %v", directive, e)
+			}
+		}
+	})
+
+	t.Run("Condition-Invalid-Type-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Conditions: []systemd.Condition{{Type: "Nonexistent", Value: "x"}},
+			},
+		}
+
+		if _, e := systemd.Marshal(daemon); e == nil {
+			t.Errorf("expected an error marshalling an unknown condition type")
+		}
+	})
+}
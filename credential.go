@@ -0,0 +1,265 @@
+package systemd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// credential validates a credential identifier. Identifiers are used as file names below $CREDENTIALS_DIRECTORY, and as such, may neither be
+// empty nor contain a slash; the colon is reserved as the identifier's delimiter.
+func credential(id string) error {
+	switch {
+	case id == "":
+		return fmt.Errorf("empty credential identifier")
+	case id == "." || id == "..":
+		return fmt.Errorf("invalid credential identifier: %q", id)
+	case strings.ContainsAny(id, "/:"):
+		return fmt.Errorf("invalid character in credential identifier: %q", id)
+	case len(id) > 255:
+		return fmt.Errorf("credential identifier exceeds 255 characters: %q", id)
+	}
+
+	return nil
+}
+
+// LoadCredential represents a single LoadCredential= or LoadCredentialEncrypted= directive: a credential read from a file or an AF_UNIX
+// stream socket.
+//
+//   - If Path is empty, the credential of the same identifier is loaded from the service manager's own credentials.
+//   - If Path is relative, it's resolved below the system's credential store directories (e.g. /etc/credstore).
+//
+// Example:
+//
+//	LoadCredential{ID: "tls.key", Path: "/etc/ssl/private/example.key"} // LoadCredential=tls.key:/etc/ssl/private/example.key
+//
+// See [systemd.exec] for additional details.
+//
+// [systemd.exec]: https://www.freedesktop.org/software/systemd/man/latest/systemd.exec.html#Credentials
+type LoadCredential struct {
+	ID   string `json:"ID" yaml:"ID"`                         // Specifies the credential's name, as exposed below $CREDENTIALS_DIRECTORY.
+	Path string `json:"Path,omitempty" yaml:"Path,omitempty"` // Specifies the file or AF_UNIX socket the credential is read from.
+}
+
+func (l LoadCredential) MarshalText() ([]byte, error) {
+	if e := credential(l.ID); e != nil {
+		return nil, e
+	}
+
+	if l.Path == "" {
+		return []byte(l.ID), nil
+	}
+
+	return []byte(l.ID + ":" + l.Path), nil
+}
+
+func (l *LoadCredential) UnmarshalText(content []byte) error {
+	id, path, _ := strings.Cut(string(content), ":")
+	if e := credential(id); e != nil {
+		return e
+	}
+
+	l.ID, l.Path = id, path
+
+	return nil
+}
+
+// SetCredential represents a single SetCredential= directive: a credential with inline data. Data is written with C-style escapes, so binary
+// data, newlines and surrounding whitespace survive the unit file's syntax.
+//
+// Example:
+//
+//	SetCredential{ID: "motd", Data: []byte("Hello\nWorld")} // SetCredential=motd:Hello\nWorld
+type SetCredential struct {
+	ID   string `json:"ID" yaml:"ID"`     // Specifies the credential's name, as exposed below $CREDENTIALS_DIRECTORY.
+	Data []byte `json:"Data" yaml:"Data"` // Specifies the credential's contents.
+}
+
+func (s SetCredential) MarshalText() ([]byte, error) {
+	if e := credential(s.ID); e != nil {
+		return nil, e
+	}
+
+	return []byte(s.ID + ":" + escape(s.Data)), nil
+}
+
+func (s *SetCredential) UnmarshalText(content []byte) error {
+	id, data, valid := strings.Cut(string(content), ":")
+	if !(valid) {
+		return fmt.Errorf("missing credential data: %q", string(content))
+	}
+
+	if e := credential(id); e != nil {
+		return e
+	}
+
+	v, e := unescape(data)
+	if e != nil {
+		return fmt.Errorf("invalid credential data of %q: %w", id, e)
+	}
+
+	s.ID, s.Data = id, v
+
+	return nil
+}
+
+// EncryptedCredential represents a single SetCredentialEncrypted= directive: an encrypted credential (as produced by systemd-creds encrypt),
+// written as base64.
+type EncryptedCredential struct {
+	ID   string `json:"ID" yaml:"ID"`     // Specifies the credential's name, as exposed below $CREDENTIALS_DIRECTORY.
+	Data []byte `json:"Data" yaml:"Data"` // Specifies the encrypted credential blob (decoded; not base64).
+}
+
+func (c EncryptedCredential) MarshalText() ([]byte, error) {
+	if e := credential(c.ID); e != nil {
+		return nil, e
+	}
+
+	return []byte(c.ID + ":" + base64.StdEncoding.EncodeToString(c.Data)), nil
+}
+
+// UnmarshalText decodes the credential's base64 blob. Whitespace within the blob (e.g. from line continuations) is ignored, as systemd does.
+func (c *EncryptedCredential) UnmarshalText(content []byte) error {
+	id, data, valid := strings.Cut(string(content), ":")
+	if !(valid) {
+		return fmt.Errorf("missing credential data: %q", string(content))
+	}
+
+	if e := credential(id); e != nil {
+		return e
+	}
+
+	v, e := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+	if e != nil {
+		return fmt.Errorf("invalid base64 credential data of %q: %w", id, e)
+	}
+
+	c.ID, c.Data = id, v
+
+	return nil
+}
+
+// escape returns the C-style escaped form of the given data, as understood by systemd's cunescape(). Leading and trailing whitespace is
+// escaped, as it would otherwise be stripped from the directive's value.
+func escape(data []byte) string {
+	var builder strings.Builder
+
+	for idx, b := range data {
+		switch b {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\t':
+			builder.WriteString(`\t`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '"':
+			builder.WriteString(`\"`)
+		case '\'':
+			builder.WriteString(`\'`)
+		case ' ':
+			if idx == 0 || idx == len(data)-1 {
+				builder.WriteString(`\x20`)
+				continue
+			}
+
+			builder.WriteByte(b)
+		default:
+			if b < 0x20 || b >= 0x7f {
+				builder.WriteString(fmt.Sprintf(`\x%02x`, b))
+				continue
+			}
+
+			builder.WriteByte(b)
+		}
+	}
+
+	return builder.String()
+}
+
+// unescape reverses [escape], supporting the escape sequences of systemd's cunescape(): \a \b \f \n \r \t \v \\ \" \' \s, \xNN, octal \NNN,
+// and \uNNNN and \UNNNNNNNN code points.
+func unescape(v string) ([]byte, error) {
+	output := make([]byte, 0, len(v))
+
+	for idx := 0; idx < len(v); idx++ {
+		if v[idx] != '\\' {
+			output = append(output, v[idx])
+			continue
+		}
+
+		if idx+1 >= len(v) {
+			return nil, fmt.Errorf("trailing backslash")
+		}
+
+		idx++
+
+		switch c := v[idx]; c {
+		case 'a':
+			output = append(output, '\a')
+		case 'b':
+			output = append(output, '\b')
+		case 'f':
+			output = append(output, '\f')
+		case 'n':
+			output = append(output, '\n')
+		case 'r':
+			output = append(output, '\r')
+		case 't':
+			output = append(output, '\t')
+		case 'v':
+			output = append(output, '\v')
+		case 's':
+			output = append(output, ' ')
+		case '\\', '"', '\'':
+			output = append(output, c)
+		case 'x':
+			if idx+2 >= len(v) {
+				return nil, fmt.Errorf("truncated \\x escape sequence")
+			}
+
+			n, e := strconv.ParseUint(v[idx+1:idx+3], 16, 8)
+			if e != nil {
+				return nil, fmt.Errorf("invalid \\x escape sequence: %w", e)
+			}
+
+			output = append(output, byte(n))
+			idx += 2
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+
+			if idx+size >= len(v) {
+				return nil, fmt.Errorf("truncated \\%c escape sequence", c)
+			}
+
+			n, e := strconv.ParseUint(v[idx+1:idx+1+size], 16, 32)
+			if e != nil {
+				return nil, fmt.Errorf("invalid \\%c escape sequence: %w", c, e)
+			}
+
+			output = append(output, string(rune(n))...)
+			idx += size
+		case '0', '1', '2', '3':
+			if idx+2 >= len(v) {
+				return nil, fmt.Errorf("truncated octal escape sequence")
+			}
+
+			n, e := strconv.ParseUint(v[idx:idx+3], 8, 8)
+			if e != nil {
+				return nil, fmt.Errorf("invalid octal escape sequence: %w", e)
+			}
+
+			output = append(output, byte(n))
+			idx += 2
+		default:
+			return nil, fmt.Errorf("invalid escape sequence: \\%c", c)
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,87 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestCredential(t *testing.T) {
+	t.Run("Credential-Marshal-Unmarshal-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Description: "Credentialed Daemon",
+			},
+			Service: systemd.Service{
+				ExecStart: []string{"/usr/bin/example-agent"},
+				Exec: systemd.Exec{
+					LoadCredential: []systemd.LoadCredential{
+						{ID: "tls.key", Path: "/etc/ssl/private/example.key"},
+						{ID: "token"},
+					},
+					LoadCredentialEncrypted: []systemd.LoadCredential{
+						{ID: "database", Path: "/etc/credstore.encrypted/database.cred"},
+					},
+					SetCredential: []systemd.SetCredential{
+						{ID: "motd", Data: []byte(" Hello\n\tWorld\\ ")},
+						{ID: "binary", Data: []byte{0x00, 0xff}},
+					},
+					SetCredentialEncrypted: []systemd.EncryptedCredential{
+						{ID: "secret", Data: []byte("encrypted-blob")},
+					},
+					ImportCredential: []string{"example.*"},
+				},
+			},
+		}
+
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectations := []string{
+			"LoadCredential=tls.key:/etc/ssl/private/example.key\n",
+			"LoadCredential=token\n",
+			"LoadCredentialEncrypted=database:/etc/credstore.encrypted/database.cred\n",
+			"SetCredential=motd:\\x20Hello\\n\\tWorld\\\\\\x20\n",
+			"SetCredential=binary:\\x00\\xff\n",
+			"SetCredentialEncrypted=secret:ZW5jcnlwdGVkLWJsb2I=\n",
+			"ImportCredential=example.*\n",
+		}
+
+		for _, expectation := range expectations {
+			if !(strings.Contains(string(content), expectation)) {
+				t.Errorf("expected %q in marshalled output:\n%s", expectation, string(content))
+			}
+		}
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Service, daemon.Service)) {
+			t.Errorf("unexpected service after round-trip:\n%+v\n%+v", instance.Service, daemon.Service)
+		}
+	})
+
+	t.Run("Credential-Invalid-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Service: systemd.Service{
+				Exec: systemd.Exec{
+					LoadCredential: []systemd.LoadCredential{{ID: "invalid/identifier", Path: "/etc/example"}},
+				},
+			},
+		}
+
+		if _, e := systemd.Marshal(daemon); e == nil {
+			t.Errorf("expected an error marshalling an invalid credential identifier")
+		}
+
+		if _, e := systemd.Unmarshal([]byte("[Service]\nSetCredentialEncrypted=secret:not base64!\n")); e == nil {
+			t.Errorf("expected an error unmarshalling invalid base64 credential data")
+		}
+	})
+}
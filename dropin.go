@@ -0,0 +1,232 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DropIns returns the names of the drop-in directories applying to the given unit name, from the most to the least specific:
+//
+//   - The unit's own drop-in directory, e.g. "foo-bar@baz.service.d".
+//   - For instances of template units, the template's drop-in directory, e.g. "foo-bar@.service.d".
+//   - Prefix drop-in directories for each dash-separated component of the unit's prefix, from the longest to the shortest; e.g. "foo-.service.d".
+//   - The type-wide drop-in directory, e.g. "service.d".
+//
+// Drop-ins of the same file name in a more specific directory take precedence over those in less specific directories. See [systemd.unit] for
+// additional details.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Description
+func DropIns(name string) ([]string, error) {
+	v, e := parseName(name)
+	if e != nil {
+		return nil, e
+	}
+
+	suffix := v.kind.Suffix() + ".d"
+
+	directories := []string{name + ".d"}
+	if v.instance != "" {
+		directories = append(directories, v.prefix+"@"+suffix)
+	}
+
+	for idx := strings.LastIndex(v.prefix, "-"); idx > 0; idx = strings.LastIndex(v.prefix[:idx], "-") {
+		directories = append(directories, v.prefix[:idx+1]+suffix)
+	}
+
+	return append(directories, string(v.kind)+".d"), nil
+}
+
+// realpath returns the given path of the file system with symbolic links resolved, along with whether it's a mask: a symbolic link to /dev/null, or
+// an empty file. Symbolic links are resolved through the file system (see [ReadLinkFS]), such that absolute destinations are interpreted relative
+// to its root, rather than the host's.
+func realpath(fsys fs.FS, p string) (string, bool, error) {
+	destination, masked, e := Resolver{FS: fsys}.follow("/" + p)
+	if e != nil {
+		return "", false, e
+	}
+
+	return relative(destination), masked, nil
+}
+
+// dropins returns the paths of the "*.conf" drop-ins of the given unit names below the given directories of the file system, in the order they're
+// applied: sorted by their file name, regardless of their directory. Of drop-ins with the same file name, only the first one found is used;
+// directories are searched in order, and within each directory, the drop-in directories of [DropIns] are searched from the most specific, for
+// each of the unit's names in turn (e.g. an alias, followed by the unit it resolves to).
+//
+// Drop-ins and their directories are returned with symbolic links resolved (see realpath). Masked drop-ins aren't returned, and hide lower-priority
+// drop-ins of the same file name.
+func dropins(fsys fs.FS, directories []string, units ...string) ([]string, error) {
+	names := make([]string, 0)
+	for _, unit := range units {
+		partials, e := DropIns(unit)
+		if e != nil {
+			return nil, e
+		}
+
+		for _, partial := range partials {
+			if !(slices.Contains(names, partial)) {
+				names = append(names, partial)
+			}
+		}
+	}
+
+	found := make(map[string]string)
+	for _, directory := range directories {
+		for _, d := range names {
+			source, masked, e := realpath(fsys, path.Join(directory, d))
+			if errors.Is(e, fs.ErrNotExist) || masked {
+				continue
+			} else if e != nil {
+				return nil, e
+			}
+
+			entries, e := fs.ReadDir(fsys, source)
+			if e != nil {
+				return nil, e
+			}
+
+			for _, entry := range entries {
+				if entry.IsDir() || !(strings.HasSuffix(entry.Name(), ".conf")) {
+					continue
+				}
+
+				if _, ok := found[entry.Name()]; ok {
+					continue
+				}
+
+				p, masked, e := realpath(fsys, path.Join(source, entry.Name()))
+				if e != nil {
+					return nil, e
+				}
+
+				if masked {
+					p = ""
+				}
+
+				found[entry.Name()] = p
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(found))
+	for key, p := range found {
+		if p != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	paths := make([]string, 0, len(keys))
+	for _, key := range keys {
+		paths = append(paths, found[key])
+	}
+
+	return paths, nil
+}
+
+// overlay appends the sections of the given drop-ins to the unit file, in order, attributing their assignments to the drop-ins' absolute paths. As the document's assignments are decoded in order, later
+// assignments replace scalar directives, append to list directives, and empty assignments reset them - systemd's drop-in semantics.
+func overlay(fsys fs.FS, file *document, paths []string) error {
+	for layer, p := range paths {
+		content, e := fs.ReadFile(fsys, p)
+		if e != nil {
+			return e
+		}
+
+		dropin, e := parse(content)
+		if e != nil {
+			return fmt.Errorf("unable to parse drop-in %s: %w", p, e)
+		}
+
+		dropin.attribute("/"+p, OriginDropIn)
+
+		for _, g := range dropin.Groups {
+			section := file.section(g.Name)
+			if section == nil {
+				section = &group{Name: g.Name, Entries: make([]entry, 0)}
+				file.Groups = append(file.Groups, section)
+			}
+
+			for _, assignment := range g.Entries {
+				assignment.Layer = layer + 1
+				section.Entries = append(section.Entries, assignment)
+			}
+		}
+	}
+
+	return nil
+}
+
+// effective parses the unit file fragment of the given names, applies their drop-ins from the given directories, and decodes the merged result. The
+// first name is the unit's name; further names (e.g. the unit an alias resolves to) only contribute drop-ins. The fragment and directories are paths
+// of the file system; the returned Daemon's Source and DropIns are absolute paths.
+func effective(fsys fs.FS, fragment string, directories []string, names []string, settings ...Option) (*Daemon, error) {
+	name := names[0]
+
+	content, e := fs.ReadFile(fsys, fragment)
+	if e != nil {
+		return nil, e
+	}
+
+	file, e := parse(content)
+	if e != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", name, e)
+	}
+
+	file.attribute("/"+fragment, OriginFragment)
+
+	paths, e := dropins(fsys, directories, names...)
+	if e != nil {
+		return nil, fmt.Errorf("unable to load drop-ins of %s: %w", name, e)
+	}
+
+	if e := overlay(fsys, file, paths); e != nil {
+		return nil, fmt.Errorf("unable to load drop-ins of %s: %w", name, e)
+	}
+
+	instance, e := unmarshal(file, configure(append(settings, UnitName(name))...))
+
+	var compatibility *CompatibilityError
+	if e != nil && !(errors.As(e, &compatibility)) {
+		return nil, fmt.Errorf("unable to load %s: %w", name, e)
+	}
+
+	instance.Source = "/" + fragment
+	for _, dropin := range paths {
+		instance.DropIns = append(instance.DropIns, "/"+dropin)
+	}
+
+	return instance, e
+}
+
+// LoadEffective reads the unit file at the given path along with its drop-ins (see [DropIns]) from the unit file's directory, and returns the
+// merged, effective Daemon - as shown by systemctl cat. The Daemon's DropIns specify the applied drop-ins, in order. See [Load] for additional
+// details, and [Resolver] for loading units from the unit search path.
+func LoadEffective(p string, settings ...Option) (*Daemon, error) {
+	absolute, e := filepath.Abs(p)
+	if e != nil {
+		return nil, e
+	}
+
+	fragment := strings.TrimPrefix(filepath.ToSlash(absolute), "/")
+
+	instance, e := effective(os.DirFS("/"), fragment, []string{path.Dir(fragment)}, []string{path.Base(fragment)}, settings...)
+	if instance != nil {
+		instance.Source = p
+		for idx := range instance.Provenance {
+			if instance.Provenance[idx].File == "/"+fragment {
+				instance.Provenance[idx].File = p
+			}
+		}
+	}
+
+	return instance, e
+}
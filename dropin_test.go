@@ -0,0 +1,176 @@
+package systemd_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestDropIns(t *testing.T) {
+	t.Run("Drop-In-Directories-Test", func(t *testing.T) {
+		cases := map[string][]string{
+			"example.service":         {"example.service.d", "service.d"},
+			"foo-bar-baz.service":     {"foo-bar-baz.service.d", "foo-bar-.service.d", "foo-.service.d", "service.d"},
+			"getty@tty1.service":      {"getty@tty1.service.d", "getty@.service.d", "service.d"},
+			"tenant-a@batch.service":  {"tenant-a@batch.service.d", "tenant-a@.service.d", "tenant-.service.d", "service.d"},
+			"-.slice":                 {"-.slice.d", "slice.d"},
+			`srv-data\x2d01.mount`:    {`srv-data\x2d01.mount.d`, "srv-.mount.d", "mount.d"},
+			"multi-user.target":       {"multi-user.target.d", "multi-.target.d", "target.d"},
+			"tenant-a-batch.slice":    {"tenant-a-batch.slice.d", "tenant-a-.slice.d", "tenant-.slice.d", "slice.d"},
+			"systemd-journald.socket": {"systemd-journald.socket.d", "systemd-.socket.d", "socket.d"},
+		}
+
+		for name, expectation := range cases {
+			directories, e := systemd.DropIns(name)
+			if e != nil {
+				t.Errorf("unexpected error for %q: %v", name, e)
+				continue
+			}
+
+			if !(reflect.DeepEqual(directories, expectation)) {
+				t.Errorf("unexpected drop-in directories of %q: %v", name, directories)
+			}
+		}
+	})
+
+	t.Run("Load-Effective-Test", func(t *testing.T) {
+		directory := t.TempDir()
+
+		files := map[string]string{
+			"foo-bar.service": "[Unit]\nDescription=Original\nAfter=network.target\n\n[Service]\nExecStart=/usr/bin/foo\nExecStartPre=/usr/bin/prepare\nUser=foo\n",
+
+			// Applied in file name order, regardless of the directory.
+			"foo-bar.service.d/20-override.conf": "[Service]\nExecStart=\nExecStart=/usr/bin/foo --verbose\n",
+			"foo-.service.d/10-prefix.conf":      "[Unit]\nAfter=remote-fs.target\n\n[Service]\nExecStartPre=/usr/bin/migrate\n",
+			"service.d/30-defaults.conf":         "[Service]\nUser=\nMemoryMax=1G\n",
+
+			// Shadowed by the more specific drop-in of the same file name.
+			"service.d/20-override.conf": "[Service]\nExecStart=/usr/bin/shadowed\n",
+
+			// Ignored, as drop-ins must use the ".conf" suffix.
+			"foo-bar.service.d/README": "[Service]\nExecStart=/usr/bin/ignored\n",
+		}
+
+		for name, content := range files {
+			p := filepath.Join(directory, name)
+			if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+				t.Fatal(e)
+			}
+
+			if e := os.WriteFile(p, []byte(content), 0o644); e != nil {
+				t.Fatal(e)
+			}
+		}
+
+		instance, e := systemd.LoadEffective(filepath.Join(directory, "foo-bar.service"))
+		if e != nil {
+			t.Fatalf("failed loading effective daemon: %v", e)
+		}
+
+		if instance.Unit.Description != "Original" {
+			t.Errorf("unexpected description: %q", instance.Unit.Description)
+		}
+
+		if instance.Unit.After != "network.target remote-fs.target" {
+			t.Errorf("expected After= to be appended to: %q", instance.Unit.After)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStart, []string{"/usr/bin/foo --verbose"})) {
+			t.Errorf("expected ExecStart= to be reset and replaced: %q", instance.Service.ExecStart)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStartPre, []string{"/usr/bin/prepare", "/usr/bin/migrate"})) {
+			t.Errorf("expected ExecStartPre= to be appended to: %q", instance.Service.ExecStartPre)
+		}
+
+		if instance.Service.User != "" || instance.Service.MemoryMax != "1G" {
+			t.Errorf("expected User= to be reset, and MemoryMax= to be set: %q, %q", instance.Service.User, instance.Service.MemoryMax)
+		}
+
+		expectation := []string{
+			filepath.Join(directory, "foo-.service.d", "10-prefix.conf"),
+			filepath.Join(directory, "foo-bar.service.d", "20-override.conf"),
+			filepath.Join(directory, "service.d", "30-defaults.conf"),
+		}
+
+		if !(reflect.DeepEqual(instance.DropIns, expectation)) {
+			t.Errorf("unexpected drop-ins: %v", instance.DropIns)
+		}
+
+		if instance.Name != "foo-bar.service" || instance.Kind != systemd.KindService {
+			t.Errorf("unexpected identity: %q, %q", instance.Name, instance.Kind)
+		}
+	})
+
+	t.Run("Load-Effective-Relocation-Test", func(t *testing.T) {
+		directory := t.TempDir()
+
+		files := map[string]string{
+			// The drop-in's legacy [Service] placement is applied after the fragment's [Unit] assignment, and as such, takes precedence.
+			"example.service":           "[Unit]\nFailureAction=none\nStartLimitBurst=5\n\n[Service]\nExecStart=/usr/bin/example\n",
+			"example.service.d/10.conf": "[Service]\nFailureAction=reboot\nStartLimitBurst=3\n",
+			"example.service.d/20.conf": "[Unit]\nStartLimitBurst=7\n",
+		}
+
+		for name, content := range files {
+			p := filepath.Join(directory, name)
+			if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+				t.Fatal(e)
+			}
+
+			if e := os.WriteFile(p, []byte(content), 0o644); e != nil {
+				t.Fatal(e)
+			}
+		}
+
+		instance, e := systemd.LoadEffective(filepath.Join(directory, "example.service"))
+		if e != nil {
+			t.Fatalf("failed loading effective daemon: %v", e)
+		}
+
+		if instance.Unit.FailureAction != "reboot" || instance.Unit.StartLimitBurst != "7" {
+			t.Errorf("expected the later assignments to take precedence: %q, %q", instance.Unit.FailureAction, instance.Unit.StartLimitBurst)
+		}
+	})
+	t.Run("Load-Effective-Masked-Drop-In-Test", func(t *testing.T) {
+		directory := t.TempDir()
+
+		files := map[string]string{
+			"example.service":                  "[Service]\nExecStart=/usr/bin/example\nUser=example\n",
+			"service.d/10-hardening.conf":      "[Service]\nUser=\nDynamicUser=yes\n",
+			"example.service.d/20-memory.conf": "[Service]\nMemoryMax=1G\n",
+		}
+
+		for name, content := range files {
+			p := filepath.Join(directory, name)
+			if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+				t.Fatal(e)
+			}
+
+			if e := os.WriteFile(p, []byte(content), 0o644); e != nil {
+				t.Fatal(e)
+			}
+		}
+
+		// Masks the type-wide drop-in of the same file name.
+		if e := os.Symlink("/dev/null", filepath.Join(directory, "example.service.d", "10-hardening.conf")); e != nil {
+			t.Fatal(e)
+		}
+
+		instance, e := systemd.LoadEffective(filepath.Join(directory, "example.service"))
+		if e != nil {
+			t.Fatalf("failed loading effective daemon: %v", e)
+		}
+
+		if instance.Service.User != "example" || instance.Service.DynamicUser != "" {
+			t.Errorf("expected the masked drop-in not to be applied: %q, %q", instance.Service.User, instance.Service.DynamicUser)
+		}
+
+		if !(reflect.DeepEqual(instance.DropIns, []string{filepath.Join(directory, "example.service.d", "20-memory.conf")})) {
+			t.Errorf("unexpected drop-ins: %v", instance.DropIns)
+		}
+	})
+}
@@ -0,0 +1,408 @@
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// entry represents a single "Key=Value" assignment of a unit file.
+type entry struct {
+	Key   string
+	Value string
+	Line  int
+
+	File   string // The file the assignment was read from, if known.
+	Origin Origin // Whether the assignment was read from the unit's fragment or a drop-in, if known.
+	Layer  int    // The position of the assignment's file: 0 for the fragment, followed by its drop-ins in the order they're applied.
+}
+
+// before reports whether the assignment is applied ahead of the given assignment, i.e. whether it was read from an earlier file, or from an
+// earlier line of the same file.
+func (e entry) before(other entry) bool {
+	if e.Layer != other.Layer {
+		return e.Layer < other.Layer
+	}
+
+	return e.Line < other.Line
+}
+
+// group represents a named section of a unit file along with its ordered assignments.
+type group struct {
+	Name    string
+	Entries []entry
+}
+
+// document represents a parsed unit file. Sections are kept in the order they first appeared in; repeated section headers are folded into the
+// original section, as systemd does.
+type document struct {
+	Groups []*group
+}
+
+// section returns the group of the given name, or nil if the document doesn't contain such a section.
+func (d *document) section(name string) *group {
+	for _, g := range d.Groups {
+		if g.Name == name {
+			return g
+		}
+	}
+
+	return nil
+}
+
+// parse reads a unit file according to the rules of systemd.syntax(7):
+//
+//   - Empty lines and lines starting with "#" or ";" are ignored.
+//   - Lines ending in a backslash are concatenated with the following line, with the backslash replaced by a space character.
+//   - Comment lines in between continuation lines are ignored.
+//   - Leading and trailing whitespace of both keys and values is stripped.
+func parse(stream []byte) (*document, error) {
+	var (
+		instance = &document{Groups: make([]*group, 0)}
+		current  *group
+
+		continuation strings.Builder
+		start        int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(stream))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		if strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if continuation.Len() == 0 {
+			start = line
+		}
+
+		if strings.HasSuffix(text, "\\") {
+			continuation.WriteString(strings.TrimSuffix(text, "\\"))
+			continuation.WriteString(" ")
+			continue
+		}
+
+		continuation.WriteString(text)
+		text = strings.TrimSpace(continuation.String())
+		continuation.Reset()
+
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !(strings.HasSuffix(text, "]")) || len(text) < 3 {
+				return nil, fmt.Errorf("invalid section header on line %d: %q", start, text)
+			}
+
+			name := text[1 : len(text)-1]
+			if current = instance.section(name); current == nil {
+				current = &group{Name: name, Entries: make([]entry, 0)}
+				instance.Groups = append(instance.Groups, current)
+			}
+
+			continue
+		}
+
+		key, value, valid := strings.Cut(text, "=")
+		if !(valid) {
+			return nil, fmt.Errorf("missing assignment on line %d: %q", start, text)
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("assignment outside of a section on line %d: %q", start, text)
+		}
+
+		current.Entries = append(current.Entries, entry{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value), Line: start})
+	}
+
+	if e := scanner.Err(); e != nil {
+		return nil, e
+	}
+
+	if continuation.Len() > 0 {
+		return nil, fmt.Errorf("unterminated line continuation starting on line %d", start)
+	}
+
+	return instance, nil
+}
+
+// keyed is implemented by repeatable values whose directive name carries part of the value - e.g. ConditionPathExists= and AssertPathExists=, where
+// the struct tag specifies the "Condition" or "Assert" prefix, and the value specifies the remainder.
+type keyed interface {
+	// suffix returns the remainder of the directive's name, following the struct tag's prefix.
+	suffix() string
+
+	// assign sets the remainder of the directive's name, returning an error if the value doesn't accept the suffix.
+	assign(suffix string) error
+}
+
+// reflection walks the systemd struct tags of the given structure. Anonymous (embedded) structs, such as [Kill], are flattened into the
+// parent's set of directives, as systemd reads them from the same section.
+func reflection(instance reflect.Value) (tags []*tag) {
+	const key = "systemd"
+
+	tags = make([]*tag, 0)
+	structure := instance.Type()
+
+	for i := 0; i < structure.NumField(); i++ {
+		field := structure.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			tags = append(tags, reflection(instance.Field(i))...)
+			continue
+		}
+
+		if v, ok := field.Tag.Lookup(key); ok {
+			partials := strings.Split(v, ",")
+			for idx, partial := range partials {
+				partials[idx] = strings.TrimSpace(partial)
+			}
+
+			if partials[0] == "-" {
+				continue
+			}
+
+			var optional, prefix bool
+			if len(partials) > 1 {
+				for _, partial := range partials[1:] {
+					switch strings.ToLower(partial) {
+					case "omitempty":
+						optional = true
+					case "prefix":
+						prefix = true
+					}
+				}
+			}
+
+			attribute := &tag{
+				Name:     partials[0],
+				Field:    field.Name,
+				Optional: optional,
+				Prefix:   prefix,
+				Value:    instance.Field(i),
+			}
+
+			tags = append(tags, attribute)
+		}
+	}
+
+	return
+}
+
+// text returns the directive value of a single (non-slice) field value.
+func text(value reflect.Value) (string, error) {
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		content, e := marshaler.MarshalText()
+		if e != nil {
+			return "", e
+		}
+
+		return string(content), nil
+	}
+
+	if value.Kind() == reflect.String {
+		return value.String(), nil
+	}
+
+	return "", fmt.Errorf("unsupported directive type: %s", value.Type())
+}
+
+// values returns the assignments of a single field. Slice fields produce one assignment per element; empty scalar fields produce none.
+func (t *tag) values() ([]entry, error) {
+	value := t.Value
+	if value.Kind() != reflect.Slice {
+		v, e := text(value)
+		if e != nil {
+			return nil, fmt.Errorf("unable to export %s: %w", t.Field, e)
+		}
+
+		if v == "" {
+			return nil, nil
+		}
+
+		return []entry{{Key: t.Name, Value: v}}, nil
+	}
+
+	exports := make([]entry, 0, value.Len())
+	for idx := 0; idx < value.Len(); idx++ {
+		element := value.Index(idx)
+
+		name := t.Name
+		if t.Prefix {
+			instance, ok := element.Addr().Interface().(keyed)
+			if !(ok) {
+				return nil, fmt.Errorf("unable to export %s: %s doesn't support prefixed directives", t.Field, element.Type())
+			}
+
+			name += instance.suffix()
+		}
+
+		v, e := text(element)
+		if e != nil {
+			return nil, fmt.Errorf("unable to export %s: %w", t.Field, e)
+		}
+
+		exports = append(exports, entry{Key: name, Value: v})
+	}
+
+	return exports, nil
+}
+
+// assignments returns the ordered set of key-values to write to a systemd file for the given section structure pointer. Slice fields produce one
+// assignment per element.
+func assignments(pointer any) ([]entry, error) {
+	exports := make([]entry, 0)
+
+	for _, tag := range reflection(reflect.ValueOf(pointer).Elem()) {
+		values, e := tag.values()
+		if e != nil {
+			return nil, e
+		}
+
+		exports = append(exports, values...)
+	}
+
+	return exports, nil
+}
+
+// export represents a named section, as generated from the given section structure pointer.
+func export(name string, pointer any) (*group, error) {
+	exports, e := assignments(pointer)
+	if e != nil {
+		return nil, e
+	}
+
+	return &group{Name: name, Entries: exports}, nil
+}
+
+// bytes serializes the document's sections, separating each with an empty line.
+func (d *document) bytes() []byte {
+	var output bytes.Buffer
+
+	for idx, g := range d.Groups {
+		if idx > 0 {
+			output.WriteString("\n")
+		}
+
+		output.WriteString("[" + g.Name + "]\n")
+		for _, assignment := range g.Entries {
+			output.WriteString(assignment.Key + "=" + assignment.Value + "\n")
+		}
+	}
+
+	return output.Bytes()
+}
+
+// assign sets a single (non-slice) field value from its directive value.
+func assign(value reflect.Value, v string) error {
+	if value.CanAddr() {
+		if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return unmarshaler.UnmarshalText([]byte(v))
+		}
+	}
+
+	if value.Kind() == reflect.String {
+		value.SetString(v)
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported directive type: %s", value.Type())
+}
+
+// decode maps the assignments of the given section onto the section structure pointer. Assignments are applied in order:
+//
+//   - Scalar directives are replaced by later assignments.
+//   - Space-separated list directives held by a string field (e.g. After=) are appended to by later assignments, per the [Directive] registry.
+//   - Repeatable (slice) directives are appended to by later assignments.
+//   - An empty assignment resets the directive, including any previously appended values - except for lists that can't be reset, such as
+//     dependencies (e.g. After=), where it's ignored.
+//
+// Unknown directives are ignored, with the exception of prefixed directives of an unknown kind (e.g. "ConditionPathExsts="), for which an error is
+// returned rather than silently dropping the check.
+func decode(section *group, pointer any) error {
+	if section == nil {
+		return nil
+	}
+
+	tags := reflection(reflect.ValueOf(pointer).Elem())
+
+	fields := make(map[string]*tag, len(tags))
+	prefixes := make([]*tag, 0)
+	for _, tag := range tags {
+		if tag.Prefix {
+			prefixes = append(prefixes, tag)
+			continue
+		}
+
+		fields[tag.Name] = tag
+	}
+
+	for _, assignment := range section.Entries {
+		tag, suffix := fields[assignment.Key], ""
+		if tag == nil {
+			for _, candidate := range prefixes {
+				if remainder, ok := strings.CutPrefix(assignment.Key, candidate.Name); ok && remainder != "" {
+					tag, suffix = candidate, remainder
+					break
+				}
+			}
+		}
+
+		if tag == nil {
+			continue
+		}
+
+		value := tag.Value
+		if value.Kind() != reflect.Slice {
+			v := assignment.Value
+			if directive, ok := Lookup(section.Name, assignment.Key); ok && directive.Repeatable && directive.Type.List() {
+				if v == "" && !(directive.Resettable) {
+					continue
+				}
+
+				if existing, e := text(value); e == nil && existing != "" && v != "" {
+					v = existing + " " + v
+				}
+			}
+
+			if e := assign(value, v); e != nil {
+				return fmt.Errorf("invalid %s= assignment on line %d: %w", assignment.Key, assignment.Line, e)
+			}
+
+			continue
+		}
+
+		if assignment.Value == "" {
+			value.Set(reflect.Zero(value.Type()))
+			continue
+		}
+
+		element := reflect.New(value.Type().Elem()).Elem()
+		if tag.Prefix {
+			instance, ok := element.Addr().Interface().(keyed)
+			if !(ok) {
+				return fmt.Errorf("unable to decode %s: %s doesn't support prefixed directives", tag.Field, element.Type())
+			}
+
+			if e := instance.assign(suffix); e != nil {
+				return fmt.Errorf("invalid %s= assignment on line %d: %w", assignment.Key, assignment.Line, e)
+			}
+		}
+
+		if e := assign(element, assignment.Value); e != nil {
+			return fmt.Errorf("invalid %s= assignment on line %d: %w", assignment.Key, assignment.Line, e)
+		}
+
+		value.Set(reflect.Append(value, element))
+	}
+
+	return nil
+}
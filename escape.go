@@ -0,0 +1,229 @@
+package systemd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// escapable reports whether the byte may appear unescaped within an escaped unit name component.
+func escapable(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b == ':' || b == '_' || b == '.'
+}
+
+// Escape returns the unit name component of an arbitrary string, as systemd-escape does: slashes are replaced by dashes, and any other character
+// outside of [a-zA-Z0-9:_.] - including dashes, and a leading "." - is written as a C-style "\xNN" escape. For example, Escape("tenant-a/batch")
+// returns `tenant\x2da-batch`.
+//
+// Escaped strings are suitable as template instances (see [BuildName]); see [EscapePath] for file system paths, and [Unescape] for the reverse.
+func Escape(s string) string {
+	var builder strings.Builder
+
+	for idx := 0; idx < len(s); idx++ {
+		switch b := s[idx]; {
+		case b == '/':
+			builder.WriteByte('-')
+		case b == '.' && idx == 0, !(escapable(b)):
+			builder.WriteString(fmt.Sprintf(`\x%02x`, b))
+		default:
+			builder.WriteByte(b)
+		}
+	}
+
+	return builder.String()
+}
+
+// simplify returns the components of a file system path, as systemd's path_simplify() does: redundant slashes and "." components are removed. An
+// error is returned for ".." components, including leading ones, as systemd's path_is_normalized() does.
+func simplify(p string) ([]string, error) {
+	components := make([]string, 0)
+	for _, component := range strings.Split(p, "/") {
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			return nil, fmt.Errorf("path isn't normalized: %q", p)
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// EscapePath returns the unit name component of a file system path, as systemd-escape --path does: the path is simplified (leading, trailing and
+// redundant slashes, and "." components, are removed), and then escaped (see [Escape]). The root directory escapes to "-". For example,
+// EscapePath("/srv/data-01/") returns `srv-data\x2d01`, as used by the "srv-data\x2d01.mount" unit.
+//
+// An error is returned for empty paths, and for paths with ".." components.
+func EscapePath(p string) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("empty path")
+	}
+
+	components, e := simplify(p)
+	if e != nil {
+		return "", e
+	}
+
+	if len(components) == 0 {
+		return "-", nil
+	}
+
+	return Escape(strings.Join(components, "/")), nil
+}
+
+// Unescape reverses [Escape], as systemd-escape --unescape does: dashes are replaced by slashes, and "\xNN" escapes are reversed. For example,
+// Unescape(`tenant\x2da-batch`) returns "tenant-a/batch". An error is returned for malformed escape sequences.
+func Unescape(s string) (string, error) {
+	var builder strings.Builder
+
+	for idx := 0; idx < len(s); idx++ {
+		switch s[idx] {
+		case '-':
+			builder.WriteByte('/')
+			continue
+		case '\\':
+		default:
+			builder.WriteByte(s[idx])
+			continue
+		}
+
+		if idx+3 >= len(s) || s[idx+1] != 'x' {
+			return "", fmt.Errorf("invalid escape sequence in %q", s)
+		}
+
+		b, e := strconv.ParseUint(s[idx+2:idx+4], 16, 8)
+		if e != nil {
+			return "", fmt.Errorf("invalid escape sequence in %q: %w", s, e)
+		}
+
+		builder.WriteByte(byte(b))
+		idx += 3
+	}
+
+	return builder.String(), nil
+}
+
+// UnescapePath reverses [EscapePath], as systemd-escape --unescape --path does, returning an absolute path; "-" unescapes to the root directory.
+// An error is returned if the unescaped path has leading or trailing slashes, or isn't normalized.
+func UnescapePath(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("empty path")
+	}
+
+	if s == "-" {
+		return "/", nil
+	}
+
+	v, e := Unescape(s)
+	if e != nil {
+		return "", e
+	}
+
+	if strings.HasPrefix(v, "/") || strings.HasSuffix(v, "/") {
+		return "", fmt.Errorf("invalid escaped path: %q", s)
+	}
+
+	for _, component := range strings.Split(v, "/") {
+		if component == "" || component == "." || component == ".." {
+			return "", fmt.Errorf("escaped path isn't normalized: %q", s)
+		}
+	}
+
+	return "/" + v, nil
+}
+
+// Mangle returns a valid unit name for the given string, as systemctl does for unit names given on its command line:
+//
+//   - Valid unit names are returned as-is.
+//   - Absolute paths are escaped (see [EscapePath]), and suffixed with ".device" for paths below /dev and /sys, or ".mount" otherwise - e.g.
+//     "/home" returns "home.mount".
+//   - Otherwise, slashes are replaced by dashes, characters outside of [a-zA-Z0-9:_.\-@\\] are escaped, and the suffix of the given Kind is
+//     appended unless the string already ends in a unit type suffix - e.g. Mangle("my app", KindService) returns `my\x20app.service`, and
+//     Mangle("foo/bar", KindService) returns "foo-bar.service".
+//
+// An error is returned if no valid unit name results, e.g. for an empty string.
+func Mangle(name string, kind Kind) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("empty unit name")
+	}
+
+	if ValidateName(name) == nil {
+		return name, nil
+	}
+
+	if strings.HasPrefix(name, "/") {
+		escaped, e := EscapePath(name)
+		if e != nil {
+			return "", e
+		}
+
+		suffix := KindMount.Suffix()
+		for _, prefix := range []string{"/dev", "/sys"} {
+			if name == prefix || strings.HasPrefix(name, prefix+"/") {
+				suffix = KindDevice.Suffix()
+			}
+		}
+
+		mangled := escaped + suffix
+		if e := ValidateName(mangled); e != nil {
+			return "", e
+		}
+
+		return mangled, nil
+	}
+
+	if !(kind.Valid()) {
+		return "", fmt.Errorf("invalid unit type: %q", kind)
+	}
+
+	var builder strings.Builder
+	for idx := 0; idx < len(name); idx++ {
+		switch b := name[idx]; {
+		case b == '/':
+			builder.WriteByte('-')
+			continue
+		case escapable(b) || b == '-' || b == '@' || b == '\\':
+			builder.WriteByte(b)
+			continue
+		}
+
+		builder.WriteString(fmt.Sprintf(`\x%02x`, name[idx]))
+	}
+
+	mangled := builder.String()
+	if _, e := KindOf(mangled); e != nil {
+		mangled += kind.Suffix()
+	}
+
+	if e := ValidateName(mangled); e != nil {
+		return "", e
+	}
+
+	return mangled, nil
+}
+
+// ValidateName reports whether the given string is a valid unit name, as systemd's unit_name_is_valid() does: names consist of a non-empty prefix,
+// an optional "@" followed by an instance (empty for template units), and a unit type suffix (e.g. ".service"); prefixes are limited to
+// [a-zA-Z0-9:_.\-\\], instances additionally allow "@", and names are limited to 255 characters.
+func ValidateName(name string) error {
+	_, e := parseName(name)
+
+	return e
+}
+
+// ValidateInstance reports whether the given string is a valid, non-empty template instance (e.g. "tty1"), per [ValidateName].
+func ValidateInstance(instance string) error {
+	if instance == "" {
+		return fmt.Errorf("empty unit instance")
+	}
+
+	for idx := 0; idx < len(instance); idx++ {
+		if b := instance[idx]; !(escapable(b) || b == '-' || b == '\\' || b == '@') {
+			return fmt.Errorf("invalid character in unit instance %q: %q", instance, b)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package systemd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestEscape(t *testing.T) {
+	t.Run("Escape-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"tenant-a/batch":     `tenant\x2da-batch`,
+			"Hallöchen, Meister": `Hall\xc3\xb6chen\x2c\x20Meister`,
+			".hidden.file":       `\x2ehidden.file`,
+			"tty1":               "tty1",
+			"":                   "",
+		}
+
+		for value, expectation := range cases {
+			if v := systemd.Escape(value); v != expectation {
+				t.Errorf("unexpected escape of %q: %q", value, v)
+			}
+
+			if v, e := systemd.Unescape(expectation); e != nil || v != value {
+				t.Errorf("unexpected unescape of %q: %q (%v)", expectation, v, e)
+			}
+		}
+	})
+
+	t.Run("Escape-Path-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"/tmp//waldi/foobar/": "tmp-waldi-foobar",
+			"/":                   "-",
+			"//":                  "-",
+			"/srv/data-01":        `srv-data\x2d01`,
+			"/srv/./data/":        "srv-data",
+			"/home/.cache":        `home-.cache`,
+			"/.snapshots":         `\x2esnapshots`,
+			"var/lib":             "var-lib",
+		}
+
+		for value, expectation := range cases {
+			v, e := systemd.EscapePath(value)
+			if e != nil || v != expectation {
+				t.Errorf("unexpected escape of %q: %q (%v)", value, v, e)
+			}
+		}
+
+		for _, value := range []string{"", "/srv/../etc", "srv/..", "/../srv"} {
+			if _, e := systemd.EscapePath(value); e == nil {
+				t.Errorf("expected an error escaping %q", value)
+			}
+		}
+	})
+
+	t.Run("Unescape-Test", func(t *testing.T) {
+		if v, e := systemd.Unescape(`tmp-waldi-foobar`); e != nil || v != "tmp/waldi/foobar" {
+			t.Errorf("unexpected unescape: %q (%v)", v, e)
+		}
+
+		for _, value := range []string{`\x2`, `\y20`, `\xzz`, `trailing\`} {
+			if _, e := systemd.Unescape(value); e == nil {
+				t.Errorf("expected an error unescaping %q", value)
+			}
+		}
+	})
+
+	t.Run("Unescape-Path-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"tmp-waldi-foobar": "/tmp/waldi/foobar",
+			"-":                "/",
+			`srv-data\x2d01`:   "/srv/data-01",
+			`\x2esnapshots`:    "/.snapshots",
+		}
+
+		for value, expectation := range cases {
+			v, e := systemd.UnescapePath(value)
+			if e != nil || v != expectation {
+				t.Errorf("unexpected unescape of %q: %q (%v)", value, v, e)
+			}
+
+			if escaped, e := systemd.EscapePath(v); e != nil || escaped != value {
+				t.Errorf("unexpected round-trip of %q: %q (%v)", value, escaped, e)
+			}
+		}
+
+		for _, value := range []string{"", "-tmp", "tmp-", "tmp--waldi", "tmp-..-etc", `tmp\x2f`} {
+			if _, e := systemd.UnescapePath(value); e == nil {
+				t.Errorf("expected an error unescaping %q", value)
+			}
+		}
+	})
+
+	t.Run("Mangle-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"sshd":                             "sshd.service",
+			"sshd.socket":                      "sshd.socket",
+			"getty@tty1.service":               "getty@tty1.service",
+			"my app":                           `my\x20app.service`,
+			"/home":                            "home.mount",
+			"/srv/data-01/":                    `srv-data\x2d01.mount`,
+			"/dev/sda1":                        "dev-sda1.device",
+			"/dev/disk/by-uuid/x":              `dev-disk-by\x2duuid-x.device`,
+			"/sys/devices/virtual/block/loop0": "sys-devices-virtual-block-loop0.device",
+			"foo/bar":                          "foo-bar.service",
+		}
+
+		for value, expectation := range cases {
+			v, e := systemd.Mangle(value, systemd.KindService)
+			if e != nil || v != expectation {
+				t.Errorf("unexpected mangling of %q: %q (%v)", value, v, e)
+			}
+		}
+
+		if v, e := systemd.Mangle("backup", systemd.KindTimer); e != nil || v != "backup.timer" {
+			t.Errorf("unexpected mangling: %q (%v)", v, e)
+		}
+
+		for _, value := range []string{"", "@.service", strings.Repeat("a", 256)} {
+			if _, e := systemd.Mangle(value, systemd.KindService); e == nil {
+				t.Errorf("expected an error mangling %q", value)
+			}
+		}
+	})
+
+	t.Run("Validate-Name-Test", func(t *testing.T) {
+		for _, name := range []string{"sshd.service", "getty@tty1.service", "getty@.service", "container@web@1.service", `srv-data\x2d01.mount`, "-.slice"} {
+			if e := systemd.ValidateName(name); e != nil {
+				t.Errorf("unexpected error for %q: %v", name, e)
+			}
+		}
+
+		for _, name := range []string{"", "sshd", "sshd.unknown", "@tty1.service", "my app.service", "a/b.service", strings.Repeat("a", 248) + ".service"} {
+			if e := systemd.ValidateName(name); e == nil {
+				t.Errorf("expected an error for %q", name)
+			}
+		}
+	})
+
+	t.Run("Validate-Instance-Test", func(t *testing.T) {
+		for _, instance := range []string{"tty1", "web@1", `home-alice\x2ddata`} {
+			if e := systemd.ValidateInstance(instance); e != nil {
+				t.Errorf("unexpected error for %q: %v", instance, e)
+			}
+		}
+
+		for _, instance := range []string{"", "a b", "a/b"} {
+			if e := systemd.ValidateInstance(instance); e == nil {
+				t.Errorf("expected an error for %q", instance)
+			}
+		}
+	})
+}
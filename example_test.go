@@ -19,11 +19,13 @@ func Example() {
 			After:         "syslog.target network-online.target",
 		},
 		Service: systemd.Service{
-			Type:           "exec",
-			ExecStart:      "/usr/bin/example-agent",
-			StandardOutput: "journal",
-			StandardError:  "journal",
-			Environment:    "Variable1=value1,Variable2=value2",
+			Type:      "exec",
+			ExecStart: []string{"/usr/bin/example-agent"},
+			Exec: systemd.Exec{
+				StandardOutput: "journal",
+				StandardError:  "journal",
+				Environment:    "Variable1=value1,Variable2=value2",
+			},
 		},
 		Install: systemd.Install{
 			WantedBy: "multi-user.target",
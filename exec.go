@@ -0,0 +1,41 @@
+package systemd
+
+// Exec represents the execution environment directives of systemd.exec(5), shared by the unit types that spawn processes: [Service], [Socket],
+// [Mount] and [Swap]. Embed Exec into a section type to inherit its directives; they're read from the same section.
+//
+// See [systemd.exec] for additional details.
+//
+// [systemd.exec]: https://www.freedesktop.org/software/systemd/man/latest/systemd.exec.html
+type Exec struct {
+	Environment             string                `json:"Environment,omitempty" yaml:"Environment,omitempty" ini:"Environment,omitempty" systemd:"Environment,omitempty"`                                         // Sets environment variables for the executed processes, as space-separated VAR=VALUE assignments.
+	EnvironmentFile         string                `json:"EnvironmentFile,omitempty" yaml:"EnvironmentFile,omitempty" ini:"EnvironmentFile,omitempty" systemd:"EnvironmentFile,omitempty"`                         // Sets environment variables for the executed processes from a file. Prefixing the path with "-" ignores a missing file.
+	LoadCredential          []LoadCredential      `json:"LoadCredential,omitempty" yaml:"LoadCredential,omitempty" ini:"-" systemd:"LoadCredential,omitempty"`                                                    // Repeatable credentials read from a file or AF_UNIX socket, exposed below $CREDENTIALS_DIRECTORY. See [LoadCredential] for additional details.
+	LoadCredentialEncrypted []LoadCredential      `json:"LoadCredentialEncrypted,omitempty" yaml:"LoadCredentialEncrypted,omitempty" ini:"-" systemd:"LoadCredentialEncrypted,omitempty"`                         // Similar to LoadCredential, but the credential is decrypted (see systemd-creds) before being passed to the executed processes.
+	SetCredential           []SetCredential       `json:"SetCredential,omitempty" yaml:"SetCredential,omitempty" ini:"-" systemd:"SetCredential,omitempty"`                                                       // Repeatable credentials with inline data. See [SetCredential] for additional details.
+	SetCredentialEncrypted  []EncryptedCredential `json:"SetCredentialEncrypted,omitempty" yaml:"SetCredentialEncrypted,omitempty" ini:"-" systemd:"SetCredentialEncrypted,omitempty"`                            // Repeatable encrypted credentials with inline, base64-encoded data. See [EncryptedCredential] for additional details.
+	ImportCredential        []string              `json:"ImportCredential,omitempty" yaml:"ImportCredential,omitempty" ini:"-" systemd:"ImportCredential,omitempty"`                                              // Repeatable glob patterns of credentials imported from the service manager's own credentials (e.g. "example.*").
+	WorkingDirectory        string                `json:"WorkingDirectory,omitempty" yaml:"WorkingDirectory,omitempty" ini:"WorkingDirectory,omitempty" systemd:"WorkingDirectory,omitempty"`                     // Sets the working directory of the executed processes. Defaults to the root directory, or the user's home directory if set to "~".
+	RootDirectory           string                `json:"RootDirectory,omitempty" yaml:"RootDirectory,omitempty" ini:"RootDirectory,omitempty" systemd:"RootDirectory,omitempty"`                                 // Sets the root directory of the executed processes, changing their file system root (see chroot(2)).
+	User                    string                `json:"User,omitempty" yaml:"User,omitempty" ini:"User,omitempty" systemd:"User,omitempty"`                                                                     // Sets the UNIX user that the executed processes run as. See related (User, Group)
+	Group                   string                `json:"Group,omitempty" yaml:"Group,omitempty" ini:"Group,omitempty" systemd:"Group,omitempty"`                                                                 // Sets the UNIX group that the executed processes run as. See related (User, Group)
+	DynamicUser             string                `json:"DynamicUser,omitempty" yaml:"DynamicUser,omitempty" ini:"DynamicUser,omitempty" systemd:"DynamicUser,omitempty"`                                         // A boolean that specifies whether a UNIX user and group are allocated dynamically for the unit, as it runs. If enabled, User= and Group= need not exist.
+	SupplementaryGroups     string                `json:"SupplementaryGroups,omitempty" yaml:"SupplementaryGroups,omitempty" ini:"SupplementaryGroups,omitempty" systemd:"SupplementaryGroups,omitempty"`         // Sets the space-separated supplementary UNIX groups of the executed processes.
+	UMask                   string                `json:"UMask,omitempty" yaml:"UMask,omitempty" ini:"UMask,omitempty" systemd:"UMask,omitempty"`                                                                 // Sets the UNIX file mode creation mask of the executed processes. Defaults to 0022
+	StandardInput           string                `json:"StandardInput,omitempty" yaml:"StandardInput,omitempty" ini:"StandardInput,omitempty" systemd:"StandardInput,omitempty"`                                 // Controls where file descriptor 0 (stdin) of the executed processes is connected to. Defaults to "null".
+	StandardOutput          string                `json:"StandardOutput,omitempty" yaml:"StandardOutput,omitempty" ini:"StandardOutput,omitempty" systemd:"StandardOutput,omitempty"`                             // Controls where file descriptor 1 (stdout) of the executed processes is connected to, e.g. "journal" or "file:/var/log/example.log".
+	StandardError           string                `json:"StandardError,omitempty" yaml:"StandardError,omitempty" ini:"StandardError,omitempty" systemd:"StandardError,omitempty"`                                 // Controls where file descriptor 2 (stderr) of the executed processes is connected to. Takes the same values as StandardOutput=.
+	LimitNOFILE             string                `json:"LimitNOFILE,omitempty" yaml:"LimitNOFILE,omitempty" ini:"LimitNOFILE,omitempty" systemd:"LimitNOFILE,omitempty"`                                         // Sets the soft and hard limit on the number of open file descriptors of the executed processes, optionally as "soft:hard".
+	LimitNPROC              string                `json:"LimitNPROC,omitempty" yaml:"LimitNPROC,omitempty" ini:"LimitNPROC,omitempty" systemd:"LimitNPROC,omitempty"`                                             // Sets the soft and hard limit on the number of processes of the executed processes' user, optionally as "soft:hard".
+	TimerSlackNSec          string                `json:"TimerSlackNSec,omitempty" yaml:"TimerSlackNSec,omitempty" ini:"TimerSlackNSec,omitempty" systemd:"TimerSlackNSec,omitempty"`                             // Sets the timer slack of the executed processes, i.e. the granularity at which their timers may be coalesced. Unitless values are nanoseconds.
+	AmbientCapabilities     string                `json:"AmbientCapabilities,omitempty" yaml:"AmbientCapabilities,omitempty" ini:"AmbientCapabilities,omitempty" systemd:"AmbientCapabilities,omitempty"`         // Sets the ambient capabilities passed to the executed processes.
+	CapabilityBoundingSet   string                `json:"CapabilityBoundingSet,omitempty" yaml:"CapabilityBoundingSet,omitempty" ini:"CapabilityBoundingSet,omitempty" systemd:"CapabilityBoundingSet,omitempty"` // Controls which capabilities the executed processes retain in their bounding set.
+	ProtectSystem           string                `json:"ProtectSystem,omitempty" yaml:"ProtectSystem,omitempty" ini:"ProtectSystem,omitempty" systemd:"ProtectSystem,omitempty"`                                 // If true, mounts /usr and the boot loader directories read-only for the executed processes; "full" additionally covers /etc, "strict" the entire file system.
+	ProtectHome             string                `json:"ProtectHome,omitempty" yaml:"ProtectHome,omitempty" ini:"ProtectHome,omitempty" systemd:"ProtectHome,omitempty"`                                         // If true, makes /home, /root and /run/user inaccessible to the executed processes; "read-only" and "tmpfs" relax the restriction.
+	PrivateTmp              string                `json:"PrivateTmp,omitempty" yaml:"PrivateTmp,omitempty" ini:"PrivateTmp,omitempty" systemd:"PrivateTmp,omitempty"`                                             // If true, sets up a new, private /tmp and /var/tmp for the executed processes.
+	PrivateDevices          string                `json:"PrivateDevices,omitempty" yaml:"PrivateDevices,omitempty" ini:"PrivateDevices,omitempty" systemd:"PrivateDevices,omitempty"`                             // If true, sets up a minimal /dev for the executed processes, without access to physical devices.
+	PrivateNetwork          string                `json:"PrivateNetwork,omitempty" yaml:"PrivateNetwork,omitempty" ini:"PrivateNetwork,omitempty" systemd:"PrivateNetwork,omitempty"`                             // If true, sets up a new network namespace for the executed processes, with only a loopback device.
+	ReadWritePaths          string                `json:"ReadWritePaths,omitempty" yaml:"ReadWritePaths,omitempty" ini:"ReadWritePaths,omitempty" systemd:"ReadWritePaths,omitempty"`                             // Space-separated paths that remain writable for the executed processes, e.g. in combination with ProtectSystem=strict.
+	ReadOnlyPaths           string                `json:"ReadOnlyPaths,omitempty" yaml:"ReadOnlyPaths,omitempty" ini:"ReadOnlyPaths,omitempty" systemd:"ReadOnlyPaths,omitempty"`                                 // Space-separated paths that are made read-only for the executed processes.
+	InaccessiblePaths       string                `json:"InaccessiblePaths,omitempty" yaml:"InaccessiblePaths,omitempty" ini:"InaccessiblePaths,omitempty" systemd:"InaccessiblePaths,omitempty"`                 // Space-separated paths that are made inaccessible for the executed processes.
+	NoNewPrivileges         string                `json:"NoNewPrivileges,omitempty" yaml:"NoNewPrivileges,omitempty" ini:"NoNewPrivileges,omitempty" systemd:"NoNewPrivileges,omitempty"`                         // If true, ensures the executed processes and their children can never gain new privileges (e.g. through setuid binaries).
+}
@@ -1,7 +1,3 @@
 module github.com/poly-gun/systemd
 
 go 1.21
-
-require gopkg.in/ini.v1 v1.67.0
-
-require github.com/stretchr/testify v1.10.0 // indirect
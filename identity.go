@@ -0,0 +1,150 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// identity represents the components of a unit name, e.g. "getty@tty1.service".
+type identity struct {
+	prefix   string // The unit name's prefix, e.g. "getty".
+	instance string // The instance of an instantiated template unit, e.g. "tty1"; empty for templates and regular units.
+	template bool   // Whether the unit name is a template, e.g. "getty@.service".
+	kind     Kind   // The unit type, e.g. "service".
+}
+
+// parseName splits a unit name into its components. Unit names consist of a prefix, an optional "@" followed by an instance, and a unit type suffix;
+// they're limited to 255 characters of [a-zA-Z0-9:_.\-\\] (plus the "@" separator). See [ValidateName] for additional details.
+func parseName(name string) (identity, error) {
+	if len(name) > 255 {
+		return identity{}, fmt.Errorf("unit name exceeds 255 characters: %q", name)
+	}
+
+	kind, e := KindOf(name)
+	if e != nil {
+		return identity{}, e
+	}
+
+	v := strings.TrimSuffix(name, kind.Suffix())
+	for idx := 0; idx < len(v); idx++ {
+		if b := v[idx]; !(escapable(b) || b == '-' || b == '\\' || b == '@') {
+			return identity{}, fmt.Errorf("invalid character in unit name %q: %q", name, b)
+		}
+	}
+
+	// Instances may contain further "@" characters, e.g. "container@web@1.service"; prefixes may not.
+	prefix, instance, instanced := strings.Cut(v, "@")
+	if prefix == "" {
+		return identity{}, fmt.Errorf("unit name without a prefix: %q", name)
+	}
+
+	return identity{prefix: prefix, instance: instance, template: instanced && instance == "", kind: kind}, nil
+}
+
+// BuildName returns the unit name of the given prefix, instance and unit type; e.g. "getty@tty1.service" for BuildName("getty", "tty1",
+// KindService). If the instance is empty, the name of a regular (non-template) unit is returned; see [TemplateName] for template names.
+//
+// The prefix and instance are expected to be escaped already (e.g. with systemd-escape); unit names are limited to [a-zA-Z0-9:_.\-].
+func BuildName(prefix, instance string, kind Kind) (string, error) {
+	if strings.Contains(prefix, "@") {
+		return "", fmt.Errorf("invalid character in unit prefix %q: '@'", prefix)
+	}
+
+	name := prefix + kind.Suffix()
+	if instance != "" {
+		name = prefix + "@" + instance + kind.Suffix()
+	}
+
+	if _, e := parseName(name); e != nil {
+		return "", e
+	}
+
+	return name, nil
+}
+
+// TemplateName returns the template unit name of the given prefix and unit type; e.g. "getty@.service" for TemplateName("getty", KindService).
+func TemplateName(prefix string, kind Kind) (string, error) {
+	if strings.Contains(prefix, "@") {
+		return "", fmt.Errorf("invalid character in unit prefix %q: '@'", prefix)
+	}
+
+	name := prefix + "@" + kind.Suffix()
+	if _, e := parseName(name); e != nil {
+		return "", e
+	}
+
+	return name, nil
+}
+
+// Prefix returns the prefix of the Daemon's unit name, e.g. "getty" for "getty@tty1.service", or an empty string if the Daemon's Name is unset or
+// invalid.
+func (d Daemon) Prefix() string {
+	v, _ := parseName(d.Name)
+
+	return v.prefix
+}
+
+// Instance returns the instance of the Daemon's unit name, e.g. "tty1" for "getty@tty1.service", or an empty string if the unit isn't an instance
+// of a template unit.
+func (d Daemon) Instance() string {
+	v, _ := parseName(d.Name)
+
+	return v.instance
+}
+
+// Template reports whether the Daemon's unit name is a template unit name, e.g. "getty@.service".
+func (d Daemon) Template() bool {
+	v, _ := parseName(d.Name)
+
+	return v.template
+}
+
+// Load reads and unmarshals the unit file at the given path. The returned Daemon's Name and Source are set from the path; its Kind is derived from the
+// file name's suffix, and its Provenance refers to the path. See [Unmarshal] for additional details.
+func Load(path string, settings ...Option) (*Daemon, error) {
+	content, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	file, e := parse(content)
+	if e != nil {
+		return nil, fmt.Errorf("unable to load %s: %w", path, e)
+	}
+
+	file.attribute(path, OriginFragment)
+
+	instance, e := unmarshal(file, configure(append(settings, UnitName(filepath.Base(path)))...))
+
+	var compatibility *CompatibilityError
+	if e != nil && !(errors.As(e, &compatibility)) {
+		return nil, fmt.Errorf("unable to load %s: %w", path, e)
+	}
+
+	instance.Source = path
+
+	return instance, e
+}
+
+// Write marshals the Daemon into a unit file of the Daemon's Name below the given directory (e.g. "/etc/systemd/system"), returning the file's path.
+// Nothing is written if marshalling fails, including a [CompatibilityError]. See [Marshal] for additional details.
+func (d Daemon) Write(directory string, settings ...Option) (string, error) {
+	if d.Name == "" {
+		return "", fmt.Errorf("unable to write a unit without a name")
+	}
+
+	content, e := Marshal(d, settings...)
+	if e != nil {
+		return "", e
+	}
+
+	path := filepath.Join(directory, d.Name)
+	if e := os.WriteFile(path, append(content, '\n'), 0o644); e != nil {
+		return "", e
+	}
+
+	return path, nil
+}
@@ -0,0 +1,100 @@
+package systemd_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestIdentity(t *testing.T) {
+	t.Run("Build-Name-Test", func(t *testing.T) {
+		name, e := systemd.BuildName("getty", "tty1", systemd.KindService)
+		if e != nil || name != "getty@tty1.service" {
+			t.Errorf("unexpected instance name: %q (%v)", name, e)
+		}
+
+		name, e = systemd.BuildName("example-agent", "", systemd.KindService)
+		if e != nil || name != "example-agent.service" {
+			t.Errorf("unexpected unit name: %q (%v)", name, e)
+		}
+
+		name, e = systemd.TemplateName("getty", systemd.KindService)
+		if e != nil || name != "getty@.service" {
+			t.Errorf("unexpected template name: %q (%v)", name, e)
+		}
+
+		for _, prefix := range []string{"", "white space", "a@b"} {
+			if _, e := systemd.BuildName(prefix, "instance", systemd.KindService); e == nil {
+				t.Errorf("expected an error for prefix %q", prefix)
+			}
+		}
+	})
+
+	t.Run("Name-Components-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{Name: "getty@tty1.service"}
+		if daemon.Prefix() != "getty" || daemon.Instance() != "tty1" || daemon.Template() {
+			t.Errorf("unexpected components of %q: %q, %q, %t", daemon.Name, daemon.Prefix(), daemon.Instance(), daemon.Template())
+		}
+
+		daemon = systemd.Daemon{Name: "getty@.service"}
+		if daemon.Prefix() != "getty" || daemon.Instance() != "" || !(daemon.Template()) {
+			t.Errorf("unexpected components of %q: %q, %q, %t", daemon.Name, daemon.Prefix(), daemon.Instance(), daemon.Template())
+		}
+	})
+
+	t.Run("Write-Load-Test", func(t *testing.T) {
+		directory := t.TempDir()
+
+		daemon := systemd.Daemon{
+			Name: "example-agent.service",
+			Unit: systemd.Unit{
+				Description: "Example Agent",
+			},
+			Service: systemd.Service{
+				ExecStart: []string{"/usr/bin/example-agent"},
+			},
+		}
+
+		path, e := daemon.Write(directory)
+		if e != nil {
+			t.Fatalf("failed writing daemon: %v", e)
+		}
+
+		if path != filepath.Join(directory, "example-agent.service") {
+			t.Errorf("unexpected unit file path: %q", path)
+		}
+
+		instance, e := systemd.Load(path)
+		if e != nil {
+			t.Fatalf("failed loading daemon: %v", e)
+		}
+
+		if instance.Name != daemon.Name || instance.Kind != systemd.KindService || instance.Source != path {
+			t.Errorf("unexpected identity of loaded daemon: %q, %q, %q", instance.Name, instance.Kind, instance.Source)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStart, daemon.Service.ExecStart)) {
+			t.Errorf("unexpected exec-start of loaded daemon: %q", instance.Service.ExecStart)
+		}
+	})
+
+	t.Run("Write-Mismatch-Test", func(t *testing.T) {
+		directory := t.TempDir()
+
+		daemon := systemd.Daemon{Name: "example.timer", Service: systemd.Service{ExecStart: []string{"/usr/bin/example"}}}
+		if _, e := daemon.Write(directory); e == nil {
+			t.Errorf("expected an error for a [Service] section in a timer unit")
+		}
+
+		if _, e := os.Stat(filepath.Join(directory, "example.timer")); !(os.IsNotExist(e)) {
+			t.Errorf("expected no unit file to be written")
+		}
+
+		if _, e := (systemd.Daemon{}).Write(directory); e == nil {
+			t.Errorf("expected an error for a unit without a name")
+		}
+	})
+}
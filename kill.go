@@ -0,0 +1,132 @@
+package systemd
+
+import (
+	"strings"
+)
+
+// KillMode represents the possible values of the KillMode= directive.
+//
+// See [systemd.kill] for additional details.
+//
+// [systemd.kill]: https://www.freedesktop.org/software/systemd/man/latest/systemd.kill.html
+type KillMode string
+
+const (
+	KillModeControlGroup KillMode = "control-group" // All remaining processes in the control group of the unit will be killed on unit stop (default).
+	KillModeMixed        KillMode = "mixed"         // The SIGTERM signal is sent to the main process while the subsequent SIGKILL signal is sent to all remaining processes of the unit's control group.
+	KillModeProcess      KillMode = "process"       // Only the main process itself is killed (not recommended).
+	KillModeNone         KillMode = "none"          // No process is killed (strongly recommended against).
+)
+
+// Valid reports whether the KillMode is one of the values understood by systemd.
+func (k KillMode) Valid() bool {
+	switch k {
+	case KillModeControlGroup, KillModeMixed, KillModeProcess, KillModeNone:
+		return true
+	}
+
+	return false
+}
+
+// String returns the KillMode's directive value.
+func (k KillMode) String() string {
+	return string(k)
+}
+
+// Signal represents a signal name as accepted by the KillSignal=, RestartKillSignal=, FinalKillSignal= and WatchdogSignal= directives.
+//
+//   - systemd accepts both the "SIGTERM" and the "TERM" spellings; the constants below use the former.
+type Signal string
+
+const (
+	SIGHUP    Signal = "SIGHUP"
+	SIGINT    Signal = "SIGINT"
+	SIGQUIT   Signal = "SIGQUIT"
+	SIGILL    Signal = "SIGILL"
+	SIGTRAP   Signal = "SIGTRAP"
+	SIGABRT   Signal = "SIGABRT"
+	SIGBUS    Signal = "SIGBUS"
+	SIGFPE    Signal = "SIGFPE"
+	SIGKILL   Signal = "SIGKILL"
+	SIGUSR1   Signal = "SIGUSR1"
+	SIGSEGV   Signal = "SIGSEGV"
+	SIGUSR2   Signal = "SIGUSR2"
+	SIGPIPE   Signal = "SIGPIPE"
+	SIGALRM   Signal = "SIGALRM"
+	SIGTERM   Signal = "SIGTERM"
+	SIGSTKFLT Signal = "SIGSTKFLT"
+	SIGCHLD   Signal = "SIGCHLD"
+	SIGCONT   Signal = "SIGCONT"
+	SIGSTOP   Signal = "SIGSTOP"
+	SIGTSTP   Signal = "SIGTSTP"
+	SIGTTIN   Signal = "SIGTTIN"
+	SIGTTOU   Signal = "SIGTTOU"
+	SIGURG    Signal = "SIGURG"
+	SIGXCPU   Signal = "SIGXCPU"
+	SIGXFSZ   Signal = "SIGXFSZ"
+	SIGVTALRM Signal = "SIGVTALRM"
+	SIGPROF   Signal = "SIGPROF"
+	SIGWINCH  Signal = "SIGWINCH"
+	SIGIO     Signal = "SIGIO"
+	SIGPWR    Signal = "SIGPWR"
+	SIGSYS    Signal = "SIGSYS"
+)
+
+// signals represents the set of standard signal names, keyed by their canonical "SIG"-prefixed spelling.
+var signals = map[Signal]struct{}{
+	SIGHUP: {}, SIGINT: {}, SIGQUIT: {}, SIGILL: {}, SIGTRAP: {}, SIGABRT: {}, SIGBUS: {}, SIGFPE: {}, SIGKILL: {}, SIGUSR1: {}, SIGSEGV: {},
+	SIGUSR2: {}, SIGPIPE: {}, SIGALRM: {}, SIGTERM: {}, SIGSTKFLT: {}, SIGCHLD: {}, SIGCONT: {}, SIGSTOP: {}, SIGTSTP: {}, SIGTTIN: {},
+	SIGTTOU: {}, SIGURG: {}, SIGXCPU: {}, SIGXFSZ: {}, SIGVTALRM: {}, SIGPROF: {}, SIGWINCH: {}, SIGIO: {}, SIGPWR: {}, SIGSYS: {},
+}
+
+// Canonical returns the "SIG"-prefixed, upper-case spelling of the signal; e.g. "term" -> "SIGTERM".
+func (s Signal) Canonical() Signal {
+	v := strings.ToUpper(strings.TrimSpace(string(s)))
+	if v == "" || strings.HasPrefix(v, "SIG") {
+		return Signal(v)
+	}
+
+	return Signal("SIG" + v)
+}
+
+// Valid reports whether the signal is a standard signal name (in either spelling), or a real-time signal of the form "SIGRTMIN+n" or "SIGRTMAX-n".
+func (s Signal) Valid() bool {
+	canonical := s.Canonical()
+	if _, ok := signals[canonical]; ok {
+		return true
+	}
+
+	v := string(canonical)
+	for _, prefix := range []string{"SIGRTMIN", "SIGRTMAX"} {
+		if v == prefix {
+			return true
+		}
+
+		if remainder, ok := strings.CutPrefix(v, prefix); ok && len(remainder) > 1 && (remainder[0] == '+' || remainder[0] == '-') {
+			return strings.Trim(remainder[1:], "0123456789") == ""
+		}
+	}
+
+	return false
+}
+
+// String returns the signal's directive value.
+func (s Signal) String() string {
+	return string(s)
+}
+
+// Kill represents the process-killing directives of systemd.kill(5). The same set of directives is shared by the [Service], [Socket], [Mount], [Swap]
+// and [Scope] sections, and as such, Kill is intended to be embedded into the respective section structure(s).
+//
+// See [systemd.kill] for additional details.
+//
+// [systemd.kill]: https://www.freedesktop.org/software/systemd/man/latest/systemd.kill.html
+type Kill struct {
+	KillMode          KillMode `json:"KillMode,omitempty" yaml:"KillMode,omitempty" ini:"KillMode,omitempty" systemd:"KillMode,omitempty"`                                     // Specifies how processes of this unit shall be killed. One of `control-group`, `mixed`, `process`, `none`.
+	KillSignal        Signal   `json:"KillSignal,omitempty" yaml:"KillSignal,omitempty" ini:"KillSignal,omitempty" systemd:"KillSignal,omitempty"`                             // Specifies which signal to use when stopping a service. Defaults to SIGTERM.
+	RestartKillSignal Signal   `json:"RestartKillSignal,omitempty" yaml:"RestartKillSignal,omitempty" ini:"RestartKillSignal,omitempty" systemd:"RestartKillSignal,omitempty"` // Specifies which signal to use when restarting a service. Defaults to the KillSignal= value.
+	FinalKillSignal   Signal   `json:"FinalKillSignal,omitempty" yaml:"FinalKillSignal,omitempty" ini:"FinalKillSignal,omitempty" systemd:"FinalKillSignal,omitempty"`         // Specifies which signal to send to remaining processes after a timeout if SendSIGKILL= is enabled. Defaults to SIGKILL.
+	SendSIGKILL       string   `json:"SendSIGKILL,omitempty" yaml:"SendSIGKILL,omitempty" ini:"SendSIGKILL,omitempty" systemd:"SendSIGKILL,omitempty"`                         // Specifies whether to send FinalKillSignal= to remaining processes after a timeout. Defaults to "yes".
+	SendSIGHUP        string   `json:"SendSIGHUP,omitempty" yaml:"SendSIGHUP,omitempty" ini:"SendSIGHUP,omitempty" systemd:"SendSIGHUP,omitempty"`                             // Specifies whether to send SIGHUP to remaining processes immediately after sending the signal configured with KillSignal=. Defaults to "no".
+	WatchdogSignal    Signal   `json:"WatchdogSignal,omitempty" yaml:"WatchdogSignal,omitempty" ini:"WatchdogSignal,omitempty" systemd:"WatchdogSignal,omitempty"`             // Specifies which signal to use to terminate the service when the watchdog timeout expires. Defaults to SIGABRT.
+}
@@ -0,0 +1,96 @@
+package systemd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestKill(t *testing.T) {
+	t.Run("Signal-Validity-Test", func(t *testing.T) {
+		valid := []systemd.Signal{systemd.SIGTERM, "TERM", "sigkill", "SIGRTMIN+3", "SIGRTMAX-1", "SIGRTMIN"}
+		for _, signal := range valid {
+			if !(signal.Valid()) {
+				t.Errorf("expected signal %q to be valid", signal)
+			}
+		}
+
+		invalid := []systemd.Signal{"", "SIGNOPE", "SIGRTMIN+", "SIGRTMIN+x"}
+		for _, signal := range invalid {
+			if signal.Valid() {
+				t.Errorf("expected signal %q to be invalid", signal)
+			}
+		}
+
+		if v := systemd.Signal("hup").Canonical(); v != systemd.SIGHUP {
+			t.Errorf("unexpected canonical signal: %q", v)
+		}
+	})
+
+	t.Run("Kill-Marshal-Unmarshal-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Description: "Graceful Shutdown",
+			},
+			Service: systemd.Service{
+				ExecStart: []string{"/usr/bin/example-agent"},
+				Kill: systemd.Kill{
+					KillMode:       systemd.KillModeMixed,
+					KillSignal:     systemd.SIGINT,
+					SendSIGKILL:    "no",
+					WatchdogSignal: systemd.SIGABRT,
+				},
+			},
+		}
+
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		for _, expectation := range []string{"KillMode=mixed", "KillSignal=SIGINT", "SendSIGKILL=no", "WatchdogSignal=SIGABRT"} {
+			if !(strings.Contains(string(content), expectation)) {
+				t.Errorf("expected %q in marshalled output:\n%s", expectation, string(content))
+			}
+		}
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Service.Kill != daemon.Service.Kill {
+			t.Errorf("unexpected kill settings after round-trip: %+v", instance.Service.Kill)
+		}
+	})
+
+	t.Run("Kill-Socket-Mount-Test", func(t *testing.T) {
+		socket, e := systemd.Unmarshal([]byte("[Socket]\nListenStream=8080\nKillMode=process\nKillSignal=SIGINT\n"), systemd.UnitName("example.socket"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling socket: %v", e)
+		}
+
+		if socket.Socket.KillMode != systemd.KillModeProcess || socket.Socket.KillSignal != systemd.SIGINT {
+			t.Errorf("unexpected socket kill settings: %+v", socket.Socket.Kill)
+		}
+
+		mount, e := systemd.Unmarshal([]byte("[Mount]\nWhat=/dev/sdb1\nWhere=/srv/data\nKillMode=mixed\n"), systemd.UnitName("srv-data.mount"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling mount: %v", e)
+		}
+
+		if mount.Mount.KillMode != systemd.KillModeMixed {
+			t.Errorf("unexpected mount kill settings: %+v", mount.Mount.Kill)
+		}
+
+		content, e := systemd.Marshal(*mount, systemd.UnitName("srv-data.mount"))
+		if e != nil {
+			t.Fatalf("failed marshalling mount: %v", e)
+		}
+
+		if !(strings.Contains(string(content), "[Mount]\nWhat=/dev/sdb1\nWhere=/srv/data\nKillMode=mixed\n")) {
+			t.Errorf("unexpected marshalled mount:\n%s", string(content))
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind represents a unit's type, as specified by its file name suffix; e.g. "service" for "example.service".
+//
+// See [systemd.unit] for additional details.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html
+type Kind string
+
+const (
+	KindService   Kind = "service"   // A process controlled and supervised by systemd. See [Service].
+	KindSocket    Kind = "socket"    // An IPC or network socket, or FIFO, used for socket-based activation. See [Socket].
+	KindTimer     Kind = "timer"     // A timer used for timer-based activation. See [Timer].
+	KindPath      Kind = "path"      // A file system path monitored for path-based activation. See [Path].
+	KindMount     Kind = "mount"     // A file system mount point. See [Mount].
+	KindAutomount Kind = "automount" // A file system automount point. See [Automount].
+	KindSwap      Kind = "swap"      // A swap device or file. See [Swap].
+	KindTarget    Kind = "target"    // A synchronization point grouping other units; targets don't have a type-specific section.
+	KindSlice     Kind = "slice"     // A node of the cgroup tree, grouping units for resource control. See [Slice].
+	KindScope     Kind = "scope"     // A set of externally created processes. See [Scope].
+	KindDevice    Kind = "device"    // A device exposed by udev; devices don't have a type-specific section.
+)
+
+// kinds maps each known unit type to its type-specific section, or an empty string for unit types without one.
+var kinds = map[Kind]string{
+	KindService: "Service", KindSocket: "Socket", KindTimer: "Timer", KindPath: "Path", KindMount: "Mount", KindAutomount: "Automount",
+	KindSwap: "Swap", KindTarget: "", KindSlice: "Slice", KindScope: "Scope", KindDevice: "",
+}
+
+// Valid reports whether the Kind is known.
+func (k Kind) Valid() bool {
+	_, ok := kinds[k]
+
+	return ok
+}
+
+// Section returns the name of the unit type's type-specific section (e.g. "Service"), or an empty string for unit types without one (targets and
+// devices).
+func (k Kind) Section() string {
+	return kinds[k]
+}
+
+// Suffix returns the unit type's file name suffix, e.g. ".service".
+func (k Kind) Suffix() string {
+	return "." + string(k)
+}
+
+// KindOf returns the unit type of the given unit file name, e.g. [KindService] for "example.service".
+func KindOf(name string) (Kind, error) {
+	idx := strings.LastIndex(name, ".")
+	if idx <= 0 {
+		return "", fmt.Errorf("unit name without a type suffix: %q", name)
+	}
+
+	kind := Kind(name[idx+1:])
+	if !(kind.Valid()) {
+		return "", fmt.Errorf("unknown unit type of %q: %q", name, kind)
+	}
+
+	return kind, nil
+}
+
+// detect returns the unit type implied by the document's type-specific sections, or an empty string if the document doesn't contain one. An empty
+// [Service] section isn't considered type-specific, as [Daemon] always carries one.
+func detect(file *document) (Kind, error) {
+	var matches []Kind
+	for _, section := range file.Groups {
+		if section.Name == "Service" && len(section.Entries) == 0 {
+			continue
+		}
+
+		for kind, name := range kinds {
+			if name != "" && name == section.Name {
+				matches = append(matches, kind)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, kind := range matches {
+		names = append(names, "["+kind.Section()+"]")
+	}
+
+	return "", &mismatch{fmt.Sprintf("multiple type-specific sections: %s", strings.Join(names, ", "))}
+}
+
+// mismatch reports type-specific sections that don't apply to a unit's type.
+type mismatch struct {
+	message string
+}
+
+func (m *mismatch) Error() string {
+	return m.message
+}
+
+// conform verifies that the document's type-specific section, if any, applies to the given unit type.
+func conform(file *document, kind Kind) error {
+	if !(kind.Valid()) {
+		return fmt.Errorf("unknown unit type: %q", kind)
+	}
+
+	detected, e := detect(file)
+	if e != nil {
+		return e
+	}
+
+	if detected != "" && detected != kind {
+		return &mismatch{fmt.Sprintf("[%s] section doesn't apply to %s units", detected.Section(), kind)}
+	}
+
+	return nil
+}
+
+// resolve returns the unit type of a daemon, as specified by its Kind, or by the configured unit name (see [UnitName]). An empty string is returned
+// if neither is specified.
+func resolve(kind Kind, o options) (Kind, error) {
+	if o.name == "" {
+		return kind, nil
+	}
+
+	named, e := parseName(o.name)
+	if e != nil {
+		return "", e
+	}
+
+	if kind != "" && kind != named.kind {
+		return "", fmt.Errorf("unit %q doesn't match the unit type %q", o.name, kind)
+	}
+
+	return named.kind, nil
+}
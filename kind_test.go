@@ -0,0 +1,104 @@
+package systemd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestKind(t *testing.T) {
+	t.Run("Kind-Of-Test", func(t *testing.T) {
+		cases := map[string]systemd.Kind{
+			"example.service":          systemd.KindService,
+			"getty@tty1.service":       systemd.KindService,
+			"multi-user.target":        systemd.KindTarget,
+			"dev-sda.device":           systemd.KindDevice,
+			`srv-data\x2d01.automount`: systemd.KindAutomount,
+		}
+
+		for name, expectation := range cases {
+			kind, e := systemd.KindOf(name)
+			if e != nil {
+				t.Errorf("unexpected error for %q: %v", name, e)
+				continue
+			}
+
+			if kind != expectation {
+				t.Errorf("unexpected kind of %q: %q", name, kind)
+			}
+		}
+
+		for _, name := range []string{"example", ".service", "example.conf"} {
+			if _, e := systemd.KindOf(name); e == nil {
+				t.Errorf("expected an error for %q", name)
+			}
+		}
+	})
+
+	t.Run("Kind-Detection-Test", func(t *testing.T) {
+		instance, e := systemd.Unmarshal([]byte("[Unit]\nDescription=Backup\n\n[Timer]\nOnCalendar=daily\n"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Kind != systemd.KindTimer {
+			t.Errorf("expected the kind to be detected from the [Timer] section: %q", instance.Kind)
+		}
+
+		instance, e = systemd.Unmarshal([]byte("[Unit]\nDescription=Application Stack\n"), systemd.UnitName("stack.target"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Kind != systemd.KindTarget {
+			t.Errorf("expected the kind to be detected from the unit name: %q", instance.Kind)
+		}
+	})
+
+	t.Run("Kind-Mismatch-Test", func(t *testing.T) {
+		if _, e := systemd.Unmarshal([]byte("[Service]\nExecStart=/usr/bin/example\n"), systemd.UnitName("example.target")); e == nil {
+			t.Errorf("expected an error for a [Service] section in a target unit")
+		}
+
+		daemon := systemd.Daemon{Kind: systemd.KindPath, Timer: &systemd.Timer{OnBootSec: "5min"}}
+		if _, e := systemd.Marshal(daemon); e == nil {
+			t.Errorf("expected an error for a [Timer] section in a path unit")
+		}
+
+		if _, e := systemd.Marshal(systemd.Daemon{Kind: systemd.KindSocket}, systemd.UnitName("example.service")); e == nil {
+			t.Errorf("expected an error for a unit name not matching the unit's kind")
+		}
+	})
+
+	t.Run("Target-Marshal-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Kind: systemd.KindTarget,
+			Unit: systemd.Unit{
+				Description: "Application Stack",
+				Wants:       "database.service cache.service",
+			},
+			Install: systemd.Install{
+				WantedBy: "multi-user.target",
+			},
+		}
+
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"Description=Application Stack",
+			"Wants=database.service cache.service",
+			"",
+			"[Install]",
+			"WantedBy=multi-user.target",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package systemd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Conflict represents a directive both sides of a [Merge] changed in different ways. Values are the directive's assigned values on each side, in
+// order; an empty list represents an unset directive. Values of prefixed directives (e.g. ConditionPathExists=) are given in "Key=Value" form.
+type Conflict struct {
+	Section   string   // Specifies the directive's section, e.g. "Service".
+	Directive string   // Specifies the directive's key, e.g. "ExecStart".
+	Base      []string // Specifies the directive's values of the common ancestor.
+	Ours      []string // Specifies the directive's values of the local side.
+	Theirs    []string // Specifies the directive's values of the incoming side.
+}
+
+func (c Conflict) Error() string {
+	return fmt.Sprintf("[%s] %s= changed on both sides: %q (ours) vs %q (theirs)", c.Section, c.Directive, c.Ours, c.Theirs)
+}
+
+// MergeError reports the conflicting directives of a [Merge].
+type MergeError struct {
+	Conflicts []Conflict // Specifies the conflicting directives, in the order of the Daemon's sections and fields.
+}
+
+func (m *MergeError) Error() string {
+	partials := make([]string, 0, len(m.Conflicts))
+	for _, conflict := range m.Conflicts {
+		partials = append(partials, conflict.Error())
+	}
+
+	return fmt.Sprintf("unable to merge %d conflicting directive(s): %s", len(m.Conflicts), strings.Join(partials, "; "))
+}
+
+// same reports whether two field assignments are equal, disregarding their provenance.
+func same(a, b []entry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if a[idx].Key != b[idx].Key || a[idx].Value != b[idx].Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// union merges the changes of both sides to an unordered list: elements either side removed from the base are dropped, and elements either side
+// added are kept - ours in order, followed by theirs.
+func union(base, ours, theirs []entry) []entry {
+	contains := func(list []entry, v entry) bool {
+		for _, candidate := range list {
+			if candidate.Key == v.Key && candidate.Value == v.Value {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	merged := make([]entry, 0, len(ours)+len(theirs))
+	for _, v := range ours {
+		if contains(base, v) && !(contains(theirs, v)) {
+			continue
+		}
+
+		merged = append(merged, v)
+	}
+
+	for _, v := range theirs {
+		if !(contains(base, v)) && !(contains(merged, v)) {
+			merged = append(merged, v)
+		}
+	}
+
+	return merged
+}
+
+// split returns the words of a space-separated list directive's assignment as individual assignments. Quoted words, e.g.
+// `"A=1 2"` in an Environment= assignment, are kept intact; see [tokens].
+func split(values []entry) []entry {
+	fields := make([]entry, 0)
+	for _, v := range values {
+		for _, word := range tokens(v.Value) {
+			fields = append(fields, entry{Key: v.Key, Value: word})
+		}
+	}
+
+	return fields
+}
+
+// join returns the individual assignments of a space-separated list directive as a single assignment.
+func join(name string, values []entry) []entry {
+	if len(values) == 0 {
+		return nil
+	}
+
+	partials := make([]string, 0, len(values))
+	for _, v := range values {
+		partials = append(partials, v.Value)
+	}
+
+	return []entry{{Key: name, Value: strings.Join(partials, " ")}}
+}
+
+// reconcile performs the three-way merge of a single field, returning the merged assignments, and the conflict, if any:
+//
+//   - Changes made by one side only are taken from that side; equal changes made by both sides are taken once.
+//   - Commands (e.g. ExecStartPre=) are ordered, and are merged only if both sides appended to the base commands.
+//   - Other list directives (repeatable slice fields, and space-separated list directives per the [Directive] registry) are merged as sets.
+//   - Scalar directives changed on both sides conflict.
+//
+// On conflict, our assignments are returned.
+func reconcile(section string, base, ours, theirs *tag) ([]entry, *Conflict, error) {
+	b, e := base.values()
+	if e != nil {
+		return nil, nil, e
+	}
+
+	o, e := ours.values()
+	if e != nil {
+		return nil, nil, e
+	}
+
+	t, e := theirs.values()
+	if e != nil {
+		return nil, nil, e
+	}
+
+	switch {
+	case same(o, t), same(b, t):
+		return o, nil, nil
+	case same(b, o):
+		return t, nil, nil
+	}
+
+	conflict := &Conflict{Section: section, Directive: ours.Name, Base: plain(base, b), Ours: plain(ours, o), Theirs: plain(theirs, t)}
+
+	directive, registered := Lookup(section, ours.Name)
+	if ours.Value.Kind() == reflect.Slice {
+		if registered && directive.Type == TypeCommand {
+			a, ok := extends(b, o)
+			if !(ok) {
+				return o, conflict, nil
+			}
+
+			if c, ok := extends(b, t); ok {
+				return append(append(append([]entry{}, b...), a...), c...), nil, nil
+			}
+
+			return o, conflict, nil
+		}
+
+		return union(b, o, t), nil, nil
+	}
+
+	if registered && directive.Repeatable && directive.Type.List() {
+		return join(ours.Name, union(split(b), split(o), split(t))), nil, nil
+	}
+
+	return o, conflict, nil
+}
+
+// plain returns the directive values of a field's assignments.
+func plain(t *tag, values []entry) []string {
+	partials := make([]string, 0, len(values))
+	for _, v := range values {
+		if t.Prefix {
+			partials = append(partials, v.Key+"="+v.Value)
+			continue
+		}
+
+		partials = append(partials, v.Value)
+	}
+
+	return partials
+}
+
+// Merge performs a three-way merge of unit files, directive by directive - e.g. to upgrade a unit file an administrator modified in place (ours)
+// to a new version shipped by a package (theirs), given the package's previous version (base):
+//
+//   - Directives changed on one side only take that side's value.
+//   - Directives changed equally on both sides take the common value.
+//   - List directives (e.g. After= and Environment=) changed on both sides are merged: values removed by either side are dropped, and values added
+//     by either side are kept. Commands (e.g. ExecStartPre=) are ordered, and are only merged if both sides append commands.
+//   - Other directives changed on both sides conflict, and keep our value.
+//
+// The merged Daemon takes our Name, Kind and Source - deriving Kind from their Daemon, or our Name, if unset. It can be written through [Marshal],
+// or as a drop-in on top of the new version with [Override]. If any directives conflict, the merged Daemon is returned alongside a [MergeError]
+// describing each [Conflict].
+func Merge(base, ours, theirs Daemon) (*Daemon, error) {
+	var conflicts = make([]Conflict, 0)
+
+	file := &document{Groups: make([]*group, 0)}
+
+	b := reflection(reflect.ValueOf(&base).Elem())
+	o := reflection(reflect.ValueOf(&ours).Elem())
+	t := reflection(reflect.ValueOf(&theirs).Elem())
+	for idx := range o {
+		name := o[idx].Name
+
+		bases := reflection(b[idx].section())
+		locals := reflection(o[idx].section())
+		incoming := reflection(t[idx].section())
+
+		entries := make([]entry, 0)
+		for field := range locals {
+			v, conflict, e := reconcile(name, bases[field], locals[field], incoming[field])
+			if e != nil {
+				return nil, fmt.Errorf("unable to merge [%s] systemd section: %w", name, e)
+			}
+
+			if conflict != nil {
+				conflicts = append(conflicts, *conflict)
+			}
+
+			entries = append(entries, v...)
+		}
+
+		file.Groups = append(file.Groups, &group{Name: name, Entries: entries})
+	}
+
+	var instance Daemon
+	if e := instance.decode(file); e != nil {
+		return nil, e
+	}
+
+	instance.Name, instance.Source, instance.Provenance = ours.Name, ours.Source, nil
+	switch {
+	case ours.Kind != "":
+		instance.Kind = ours.Kind
+	case theirs.Kind != "":
+		instance.Kind = theirs.Kind
+	case ours.Name != "":
+		if v, e := parseName(ours.Name); e == nil {
+			instance.Kind = v.kind
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &instance, &MergeError{Conflicts: conflicts}
+	}
+
+	return &instance, nil
+}
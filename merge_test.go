@@ -0,0 +1,150 @@
+package systemd_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestMerge(t *testing.T) {
+	base := systemd.Daemon{
+		Name: "agent.service",
+		Unit: systemd.Unit{
+			Description: "Agent",
+			After:       "network.target",
+		},
+		Service: systemd.Service{
+			ExecStart:       []string{"/usr/bin/agent"},
+			ExecStartPre:    []string{"/usr/bin/agent --check"},
+			Restart:         "on-failure",
+			TimeoutStartSec: "90s",
+			Exec: systemd.Exec{
+				Environment: "MODE=production LEVEL=info",
+			},
+		},
+		Install: systemd.Install{
+			WantedBy: "multi-user.target",
+		},
+	}
+
+	t.Run("Merge-Clean-Test", func(t *testing.T) {
+		ours := base
+		ours.Service.TimeoutStartSec = "5min"
+		ours.Service.Environment = "MODE=production LEVEL=debug"
+		ours.Service.ExecStartPre = []string{"/usr/bin/agent --check", "/usr/bin/local-setup"}
+
+		theirs := base
+		theirs.Unit.Description = "Agent (v2)"
+		theirs.Unit.After = "network.target remote-fs.target"
+		theirs.Service.Restart = "always"
+		theirs.Service.Environment = "MODE=production LEVEL=info FEATURES=all"
+		theirs.Service.ExecStartPre = []string{"/usr/bin/agent --check", "/usr/bin/agent --migrate"}
+
+		merged, e := systemd.Merge(base, ours, theirs)
+		if e != nil {
+			t.Fatalf("unexpected merge error: %v", e)
+		}
+
+		if merged.Name != "agent.service" || merged.Kind != systemd.KindService {
+			t.Errorf("unexpected identity: %q, %q", merged.Name, merged.Kind)
+		}
+
+		if merged.Unit.Description != "Agent (v2)" || merged.Unit.After != "network.target remote-fs.target" {
+			t.Errorf("unexpected [Unit] section: %+v", merged.Unit)
+		}
+
+		if merged.Service.Restart != "always" || merged.Service.TimeoutStartSec != "5min" {
+			t.Errorf("unexpected scalars: %q, %q", merged.Service.Restart, merged.Service.TimeoutStartSec)
+		}
+
+		if merged.Service.Environment != "MODE=production LEVEL=debug FEATURES=all" {
+			t.Errorf("unexpected environment: %q", merged.Service.Environment)
+		}
+
+		expectation := []string{"/usr/bin/agent --check", "/usr/bin/local-setup", "/usr/bin/agent --migrate"}
+		if !(reflect.DeepEqual(merged.Service.ExecStartPre, expectation)) {
+			t.Errorf("unexpected commands: %q", merged.Service.ExecStartPre)
+		}
+
+		// The merge result is a drop-in on top of the new version.
+		content, e := systemd.Override(theirs, *merged)
+		if e != nil {
+			t.Fatalf("failed generating override: %v", e)
+		}
+
+		if string(content) != "[Service]\nExecStartPre=\nExecStartPre=/usr/bin/agent --check\nExecStartPre=/usr/bin/local-setup\nExecStartPre=/usr/bin/agent --migrate\nTimeoutStartSec=5min\nEnvironment=\nEnvironment=MODE=production LEVEL=debug FEATURES=all\n" {
+			t.Errorf("unexpected override:\n%s", string(content))
+		}
+	})
+
+	t.Run("Merge-Quoted-Environment-Test", func(t *testing.T) {
+		quoted := base
+		quoted.Service.Environment = `"A=1 2"`
+
+		ours := quoted
+		ours.Service.Environment = `"A=1 2" B=3`
+
+		theirs := quoted
+		theirs.Service.Environment = `"A=1 3"`
+
+		merged, e := systemd.Merge(quoted, ours, theirs)
+		if e != nil {
+			t.Fatalf("unexpected merge error: %v", e)
+		}
+
+		if merged.Service.Environment != `B=3 "A=1 3"` {
+			t.Errorf("unexpected environment: %q", merged.Service.Environment)
+		}
+	})
+
+	t.Run("Merge-Conflict-Test", func(t *testing.T) {
+		ours := base
+		ours.Service.TimeoutStartSec = "5min"
+		ours.Service.ExecStart = []string{"/usr/bin/agent --local"}
+
+		theirs := base
+		theirs.Service.TimeoutStartSec = "2min"
+		theirs.Service.ExecStart = []string{"/usr/bin/agent --v2"}
+		theirs.Service.Restart = "always"
+
+		merged, e := systemd.Merge(base, ours, theirs)
+
+		var exception *systemd.MergeError
+		if !(errors.As(e, &exception)) {
+			t.Fatalf("expected a merge error, received: %v", e)
+		}
+
+		if merged == nil || merged.Service.TimeoutStartSec != "5min" || merged.Service.Restart != "always" {
+			t.Errorf("unexpected merge result: %+v", merged)
+		}
+
+		expectation := []systemd.Conflict{
+			{Section: "Service", Directive: "ExecStart", Base: []string{"/usr/bin/agent"}, Ours: []string{"/usr/bin/agent --local"}, Theirs: []string{"/usr/bin/agent --v2"}},
+			{Section: "Service", Directive: "TimeoutStartSec", Base: []string{"90s"}, Ours: []string{"5min"}, Theirs: []string{"2min"}},
+		}
+
+		if !(reflect.DeepEqual(exception.Conflicts, expectation)) {
+			t.Errorf("unexpected conflicts: %+v", exception.Conflicts)
+		}
+	})
+
+	t.Run("Merge-Section-Test", func(t *testing.T) {
+		ours := base
+		ours.Timer = nil
+
+		theirs := base
+		theirs.Service.Restart = ""
+		theirs.Socket = &systemd.Socket{Accept: "yes"}
+
+		merged, e := systemd.Merge(base, ours, theirs)
+		if e != nil {
+			t.Fatalf("unexpected merge error: %v", e)
+		}
+
+		if merged.Socket == nil || merged.Socket.Accept != "yes" || merged.Service.Restart != "" {
+			t.Errorf("unexpected merge result: %+v", merged)
+		}
+	})
+}
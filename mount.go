@@ -0,0 +1,99 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mount represents the [Mount] section of a systemd mount file.
+//
+// A mount unit (e.g. "srv-data.mount") describes a file system mount point controlled by systemd. Mount units must be named after the mount point
+// they control, escaped as systemd-escape --path does; e.g. a unit with Where=/srv/data must be named "srv-data.mount". See [UnitName] for
+// checking a unit's name when marshalling.
+//
+// See [systemd.mount] for additional details.
+//
+// [systemd.mount]: https://www.freedesktop.org/software/systemd/man/latest/systemd.mount.html
+type Mount struct {
+	What          string `json:"What,omitempty" yaml:"What,omitempty" ini:"What,omitempty" systemd:"What,omitempty"`                                     // Specifies the absolute path of a device node, file or other resource to mount (e.g. "/dev/disk/by-uuid/...").
+	Where         string `json:"Where,omitempty" yaml:"Where,omitempty" ini:"Where,omitempty" systemd:"Where,omitempty"`                                 // Specifies the absolute path of the mount point. If the mount point doesn't exist, it's created as a directory.
+	Type          string `json:"Type,omitempty" yaml:"Type,omitempty" ini:"Type,omitempty" systemd:"Type,omitempty"`                                     // Specifies the file system type (e.g. "ext4", "nfs"). Optional; see mount(8).
+	Options       string `json:"Options,omitempty" yaml:"Options,omitempty" ini:"Options,omitempty" systemd:"Options,omitempty"`                         // Specifies the comma-separated mount options to use when mounting.
+	SloppyOptions string `json:"SloppyOptions,omitempty" yaml:"SloppyOptions,omitempty" ini:"SloppyOptions,omitempty" systemd:"SloppyOptions,omitempty"` // If true, unknown mount options are tolerated (see mount(8)'s -s switch). Defaults to off.
+	LazyUnmount   string `json:"LazyUnmount,omitempty" yaml:"LazyUnmount,omitempty" ini:"LazyUnmount,omitempty" systemd:"LazyUnmount,omitempty"`         // If true, the file system is detached lazily from the hierarchy when unmounted (see umount(8)'s -l switch).
+	ReadWriteOnly string `json:"ReadWriteOnly,omitempty" yaml:"ReadWriteOnly,omitempty" ini:"ReadWriteOnly,omitempty" systemd:"ReadWriteOnly,omitempty"` // If true, mounting fails instead of falling back to a read-only mount when the file system can't be mounted read-write.
+	ForceUnmount  string `json:"ForceUnmount,omitempty" yaml:"ForceUnmount,omitempty" ini:"ForceUnmount,omitempty" systemd:"ForceUnmount,omitempty"`     // If true, unmounting is forced, e.g. for an unreachable NFS file system (see umount(8)'s -f switch).
+	DirectoryMode string `json:"DirectoryMode,omitempty" yaml:"DirectoryMode,omitempty" ini:"DirectoryMode,omitempty" systemd:"DirectoryMode,omitempty"` // Specifies the access mode of automatically created mount point directories. Defaults to 0755.
+	TimeoutSec    string `json:"TimeoutSec,omitempty" yaml:"TimeoutSec,omitempty" ini:"TimeoutSec,omitempty" systemd:"TimeoutSec,omitempty"`             // Configures the time to wait for the mount command to finish before it's considered failed and shut down again.
+
+	Exec            `yaml:",inline" ini:",extends"` // Embeds the execution environment directives of systemd.exec(5), applying to the mount and umount processes of the unit. See [Exec] for additional details.
+	Kill            `yaml:",inline" ini:",extends"` // Embeds the process-killing directives of systemd.kill(5), applying to the mount and umount processes of the unit. See [Kill] for additional details.
+	ResourceControl `yaml:",inline" ini:",extends"` // Embeds the resource control directives of systemd.resource-control(5). See [ResourceControl] for additional details.
+}
+
+// Automount represents the [Automount] section of a systemd automount file.
+//
+// An automount unit (e.g. "srv-data.automount") mounts a file system on demand, when its mount point is first accessed, by activating the mount unit
+// of the same name (e.g. "srv-data.mount"). As with [Mount], the unit must be named after its escaped Where= path.
+//
+// See [systemd.automount] for additional details.
+//
+// [systemd.automount]: https://www.freedesktop.org/software/systemd/man/latest/systemd.automount.html
+type Automount struct {
+	Where          string `json:"Where,omitempty" yaml:"Where,omitempty" ini:"Where,omitempty" systemd:"Where,omitempty"`                                     // Specifies the absolute path of the automount point. If the automount point doesn't exist, it's created as a directory.
+	ExtraOptions   string `json:"ExtraOptions,omitempty" yaml:"ExtraOptions,omitempty" ini:"ExtraOptions,omitempty" systemd:"ExtraOptions,omitempty"`         // Specifies additional, comma-separated mount options for the autofs mount point.
+	DirectoryMode  string `json:"DirectoryMode,omitempty" yaml:"DirectoryMode,omitempty" ini:"DirectoryMode,omitempty" systemd:"DirectoryMode,omitempty"`     // Specifies the access mode of automatically created automount point directories. Defaults to 0755.
+	TimeoutIdleSec string `json:"TimeoutIdleSec,omitempty" yaml:"TimeoutIdleSec,omitempty" ini:"TimeoutIdleSec,omitempty" systemd:"TimeoutIdleSec,omitempty"` // Configures an idle timeout; once the mount has been idle for the specified time, an unmount is attempted. Defaults to 0 (disabled).
+}
+
+// names represents the unit types whose name is derived from the path specified by one of their directives.
+var names = []struct {
+	Suffix    string // The unit type's file name suffix, e.g. ".mount".
+	Section   string // The unit type's section, e.g. "Mount".
+	Directive string // The directive specifying the path the unit is named after, e.g. "Where".
+}{
+	{Suffix: ".mount", Section: "Mount", Directive: "Where"},
+	{Suffix: ".automount", Section: "Automount", Directive: "Where"},
+	{Suffix: ".swap", Section: "Swap", Directive: "What"},
+}
+
+// named verifies that the document's unit name matches the escaped path of its type-specific section, as systemd refuses to load mount and
+// automount units whose name differs from their Where= path, and swap units whose name differs from their What= path.
+func named(file *document, name string) error {
+	for _, rule := range names {
+		if !(strings.HasSuffix(name, rule.Suffix)) {
+			continue
+		}
+
+		section := file.section(rule.Section)
+		if section == nil {
+			return fmt.Errorf("unit %q is missing its [%s] section", name, rule.Section)
+		}
+
+		var path string
+		for _, assignment := range section.Entries {
+			if assignment.Key == rule.Directive {
+				path = assignment.Value
+			}
+		}
+
+		if path == "" {
+			return fmt.Errorf("unit %q is missing the [%s] %s= directive", name, rule.Section, rule.Directive)
+		}
+
+		if !(strings.HasPrefix(path, "/")) {
+			return fmt.Errorf("invalid [%s] %s= directive of unit %q: path isn't absolute: %q", rule.Section, rule.Directive, name, path)
+		}
+
+		escaped, e := EscapePath(path)
+		if e != nil {
+			return fmt.Errorf("invalid [%s] %s= directive of unit %q: %w", rule.Section, rule.Directive, name, e)
+		}
+
+		if expectation := escaped + rule.Suffix; name != expectation {
+			return fmt.Errorf("unit %q doesn't match its [%s] %s=%s directive; expected %q", name, rule.Section, rule.Directive, path, expectation)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+// [Unit]
+// Description=Data Volume
+//
+// [Mount]
+// What=/dev/disk/by-label/data
+// Where=/srv/data-01
+// Type=ext4
+// Options=noatime
+//
+// [Install]
+// WantedBy=local-fs.target
+func TestMount(t *testing.T) {
+	daemon := systemd.Daemon{
+		Unit: systemd.Unit{
+			Description: "Data Volume",
+		},
+		Install: systemd.Install{
+			WantedBy: "local-fs.target",
+		},
+		Mount: &systemd.Mount{
+			What:    "/dev/disk/by-label/data",
+			Where:   "/srv/data-01",
+			Type:    "ext4",
+			Options: "noatime",
+		},
+	}
+
+	t.Run("Mount-Marshal-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon, systemd.UnitName(`srv-data\x2d01.mount`))
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"Description=Data Volume",
+			"",
+			"[Mount]",
+			"What=/dev/disk/by-label/data",
+			"Where=/srv/data-01",
+			"Type=ext4",
+			"Options=noatime",
+			"",
+			"[Install]",
+			"WantedBy=local-fs.target",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Mount, daemon.Mount)) {
+			t.Errorf("unexpected mount after round-trip: %+v", instance.Mount)
+		}
+	})
+
+	t.Run("Mount-Name-Mismatch-Test", func(t *testing.T) {
+		if _, e := systemd.Marshal(daemon, systemd.UnitName("srv-data-01.mount")); e == nil {
+			t.Errorf("expected an error for a unit name not matching Where=")
+		}
+	})
+
+	t.Run("Mount-Name-Missing-Section-Test", func(t *testing.T) {
+		if _, e := systemd.Marshal(systemd.Daemon{}, systemd.UnitName("srv.mount")); e == nil {
+			t.Errorf("expected an error for a mount unit without a [Mount] section")
+		}
+	})
+
+	t.Run("Automount-Name-Test", func(t *testing.T) {
+		automount := systemd.Daemon{
+			Automount: &systemd.Automount{
+				Where:          "/",
+				TimeoutIdleSec: "10min",
+			},
+		}
+
+		if _, e := systemd.Marshal(automount, systemd.UnitName("-.automount")); e != nil {
+			t.Errorf("unexpected error for the root automount point: %v", e)
+		}
+
+		automount.Automount.Where = "//.cache/"
+		if _, e := systemd.Marshal(automount, systemd.UnitName(`\x2ecache.automount`)); e != nil {
+			t.Errorf("unexpected error for an escaped leading dot: %v", e)
+		}
+
+		automount.Automount.Where = "/srv/../data"
+		if _, e := systemd.Marshal(automount, systemd.UnitName("data.automount")); e == nil {
+			t.Errorf("expected an error for a non-normalized Where= path")
+		}
+	})
+}
@@ -0,0 +1,368 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// timespans maps the time span units of systemd.time(7) to nanoseconds.
+var timespans = map[string]float64{
+	"ns": 1, "nsec": 1,
+	"us": 1e3, "usec": 1e3, "µs": 1e3, "μs": 1e3,
+	"ms": 1e6, "msec": 1e6,
+	"s": 1e9, "sec": 1e9, "second": 1e9, "seconds": 1e9,
+	"m": 60e9, "min": 60e9, "minute": 60e9, "minutes": 60e9,
+	"h": 3600e9, "hr": 3600e9, "hour": 3600e9, "hours": 3600e9,
+	"d": 86400e9, "day": 86400e9, "days": 86400e9,
+	"w": 604800e9, "week": 604800e9, "weeks": 604800e9,
+	"M": 2629800e9, "month": 2629800e9, "months": 2629800e9,
+	"y": 31557600e9, "year": 31557600e9, "years": 31557600e9,
+}
+
+// timespan returns the canonical form of a time span, as documented by systemd.time(7): its components from weeks down to microseconds, e.g.
+// "1min 30s" for "90", "1m30s" and "1.5min". Unitless values are of the given unit (e.g. "s"); "infinity" is kept as-is. Time spans are rounded to
+// microseconds, unless the given unit is finer - e.g. nanoseconds for TimerSlackNSec=, whose components extend down to nanoseconds.
+func timespan(v string, base string) (string, error) {
+	if v == "infinity" {
+		return v, nil
+	}
+
+	var total float64
+
+	remainder := strings.TrimSpace(v)
+	if remainder == "" {
+		return "", fmt.Errorf("empty time span")
+	}
+
+	for remainder != "" {
+		idx := strings.IndexFunc(remainder, func(r rune) bool { return !(r >= '0' && r <= '9' || r == '.') })
+		if idx == 0 {
+			return "", fmt.Errorf("invalid time span: %q", v)
+		}
+
+		number, unit := remainder, ""
+		if idx > 0 {
+			number, remainder = remainder[:idx], strings.TrimLeft(remainder[idx:], " ")
+			end := strings.IndexFunc(remainder, func(r rune) bool { return r >= '0' && r <= '9' || r == ' ' })
+			if end < 0 {
+				end = len(remainder)
+			}
+
+			unit, remainder = remainder[:end], strings.TrimLeft(remainder[end:], " ")
+		} else {
+			remainder = ""
+		}
+
+		n, e := strconv.ParseFloat(number, 64)
+		if e != nil {
+			return "", fmt.Errorf("invalid time span: %q", v)
+		}
+
+		multiplier := timespans[base]
+		if unit != "" {
+			m, ok := timespans[unit]
+			if !(ok) {
+				return "", fmt.Errorf("invalid time span unit %q: %q", unit, v)
+			}
+
+			multiplier = m
+		}
+
+		total += n * multiplier
+	}
+
+	resolution := min(timespans["us"], timespans[base])
+
+	nanoseconds := uint64(math.Round(total/resolution) * resolution)
+	if nanoseconds == 0 {
+		return "0", nil
+	}
+
+	partials := make([]string, 0)
+	for _, unit := range []struct {
+		Name   string
+		Length uint64
+	}{{"w", 604800e9}, {"d", 86400e9}, {"h", 3600e9}, {"min", 60e9}, {"s", 1e9}, {"ms", 1e6}, {"us", 1e3}, {"ns", 1}} {
+		if nanoseconds >= unit.Length {
+			partials = append(partials, strconv.FormatUint(nanoseconds/unit.Length, 10)+unit.Name)
+			nanoseconds %= unit.Length
+		}
+	}
+
+	return strings.Join(partials, " "), nil
+}
+
+// size returns the canonical form of a byte size with an optional base-1024 suffix (K, M, G, T, P, E): the largest suffix that represents the
+// size exactly, e.g. "1G" for "1024M". Percentages and "infinity" are kept as-is.
+func size(v string) (string, error) {
+	if v == "infinity" || strings.HasSuffix(v, "%") || strings.HasSuffix(v, "‰") || strings.HasSuffix(v, "‱") {
+		return v, nil
+	}
+
+	const suffixes = "KMGTPE"
+
+	number, multiplier := strings.TrimSuffix(v, "B"), float64(1)
+	if n := len(number); n > 0 {
+		if idx := strings.IndexByte(suffixes, number[n-1]); idx >= 0 {
+			number, multiplier = number[:n-1], math.Pow(1024, float64(idx+1))
+		}
+	}
+
+	n, e := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if e != nil || n < 0 {
+		return "", fmt.Errorf("invalid size: %q", v)
+	}
+
+	bytes := uint64(math.Round(n * multiplier))
+	if bytes == 0 {
+		return "0", nil
+	}
+
+	suffix := ""
+	for idx := 0; idx < len(suffixes) && bytes%1024 == 0; idx++ {
+		bytes, suffix = bytes/1024, suffixes[idx:idx+1]
+	}
+
+	return strconv.FormatUint(bytes, 10) + suffix, nil
+}
+
+// numbered specifies the standard Linux signals, by number.
+var numbered = []Signal{
+	1: SIGHUP, 2: SIGINT, 3: SIGQUIT, 4: SIGILL, 5: SIGTRAP, 6: SIGABRT, 7: SIGBUS, 8: SIGFPE, 9: SIGKILL, 10: SIGUSR1, 11: SIGSEGV, 12: SIGUSR2,
+	13: SIGPIPE, 14: SIGALRM, 15: SIGTERM, 16: SIGSTKFLT, 17: SIGCHLD, 18: SIGCONT, 19: SIGSTOP, 20: SIGTSTP, 21: SIGTTIN, 22: SIGTTOU, 23: SIGURG,
+	24: SIGXCPU, 25: SIGXFSZ, 26: SIGVTALRM, 27: SIGPROF, 28: SIGWINCH, 29: SIGIO, 30: SIGPWR, 31: SIGSYS,
+}
+
+// signal returns the canonical form of a signal: its "SIG"-prefixed name, e.g. "SIGTERM" for "TERM" and "15". See [Signal.Canonical].
+func signal(v string) (string, error) {
+	if n, e := strconv.Atoi(v); e == nil && n > 0 && n < len(numbered) {
+		return numbered[n].String(), nil
+	}
+
+	if s := Signal(v); s.Valid() {
+		return s.Canonical().String(), nil
+	}
+
+	return "", fmt.Errorf("invalid signal: %q", v)
+}
+
+// tokens splits a list value into its words, keeping quoted strings (e.g. within Environment=) intact.
+func tokens(v string) []string {
+	fields := make([]string, 0)
+
+	var (
+		builder strings.Builder
+		quote   rune
+	)
+
+	for _, r := range v {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ' ' || r == '\t':
+			if builder.Len() > 0 {
+				fields = append(fields, builder.String())
+				builder.Reset()
+			}
+
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	if builder.Len() > 0 {
+		fields = append(fields, builder.String())
+	}
+
+	return fields
+}
+
+// unique returns the words without duplicates, in the order of their first occurrence.
+func unique(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	result := make([]string, 0, len(words))
+	for _, word := range words {
+		if !(seen[word]) {
+			seen[word] = true
+			result = append(result, word)
+		}
+	}
+
+	return result
+}
+
+// assignment returns the canonical form of an environment word: the assignment without its quotes, quoted as a whole where it contains
+// whitespace or quotes - e.g. `"B=x y"` for both `B="x y"` and `"B=x y"`, and `A=1` for `"A=1"`.
+func assignment(word string) string {
+	var (
+		builder strings.Builder
+		quote   rune
+	)
+
+	for _, r := range word {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+			continue
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	plain := builder.String()
+	switch {
+	case !(strings.ContainsAny(plain, " \t\"'")):
+		return plain
+	case !(strings.ContainsRune(plain, '"')):
+		return `"` + plain + `"`
+	}
+
+	return "'" + plain + "'"
+}
+
+// environment returns the effective assignments of the given environment words (e.g. `A=1 "B=two words" A=2`), in their canonical form (see
+// assignment): the last assignment of each variable, in the order the variables are first assigned in.
+func environment(words []string) []string {
+	result := make([]string, 0, len(words))
+	positions := make(map[string]int, len(words))
+	for _, word := range words {
+		word = assignment(word)
+		name, _, _ := strings.Cut(strings.TrimLeft(word, `"'`), "=")
+		if idx, ok := positions[name]; ok {
+			result[idx] = word
+			continue
+		}
+
+		positions[name] = len(result)
+		result = append(result, word)
+	}
+
+	return result
+}
+
+// canonical returns the canonical form of a directive value, according to its type per the [Directive] registry:
+//
+//   - Booleans are written as "yes" or "no".
+//   - Time spans, sizes, modes and signals are written in their canonical form; e.g. "1min 30s", "1G", "0755" and "SIGTERM".
+//   - Integers are written without redundant signs or leading zeros.
+//   - Unit and path lists are sorted, without duplicates; environment assignments are reduced to the last assignment of each variable, each quoted
+//     as a whole where needed (e.g. `"B=x y"`); other lists are single-spaced.
+//
+// Values of other (and unregistered) directives are trimmed of surrounding whitespace.
+func canonical(section, key, v string) (string, error) {
+	v = strings.TrimSpace(v)
+
+	directive, ok := Lookup(section, key)
+	if !(ok) || v == "" {
+		return v, nil
+	}
+
+	switch directive.Type {
+	case TypeBoolean:
+		switch strings.ToLower(v) {
+		case "yes", "true", "on", "1", "y", "t":
+			return "yes", nil
+		case "no", "false", "off", "0", "n", "f":
+			return "no", nil
+		}
+
+		return "", fmt.Errorf("invalid boolean: %q", v)
+	case TypeTimespan:
+		base := directive.DefaultUnit
+		if base == "" {
+			base = "s"
+		}
+
+		return timespan(v, base)
+	case TypeSize:
+		return size(v)
+	case TypeMode:
+		mode, e := strconv.ParseUint(v, 8, 32)
+		if e != nil {
+			return "", fmt.Errorf("invalid mode: %q", v)
+		}
+
+		return fmt.Sprintf("%04o", mode), nil
+	case TypeSignal:
+		return signal(v)
+	case TypeInteger:
+		if n, e := strconv.ParseInt(v, 10, 64); e == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+	case TypeUnits, TypePaths:
+		words := unique(strings.Fields(v))
+		sort.Strings(words)
+
+		return strings.Join(words, " "), nil
+	case TypeEnvironment:
+		return strings.Join(environment(tokens(v)), " "), nil
+	case TypeList:
+		return strings.Join(tokens(v), " "), nil
+	}
+
+	return v, nil
+}
+
+// Normalize returns a copy of the Daemon with each directive value rewritten into its canonical form - e.g. "true" to "yes", "90" to "1min 30s",
+// and "After=b.service a.service a.service" to "After=a.service b.service" - so that units of equal meaning marshal equally. Empty
+// type-specific sections (e.g. a non-nil, empty Timer) are removed.
+//
+// Values that can't be parsed according to their directive's type are kept as-is, and reported alongside the normalized Daemon. See [Equal]
+// for comparing units by meaning.
+func Normalize(d Daemon) (*Daemon, error) {
+	var exceptions = make([]error, 0)
+
+	e := d.specialize(func(section string, assignment entry) (string, error) {
+		v, e := canonical(section, assignment.Key, assignment.Value)
+		if e != nil {
+			exceptions = append(exceptions, fmt.Errorf("[%s] %s=: %w", section, assignment.Key, e))
+
+			return assignment.Value, nil
+		}
+
+		return v, nil
+	})
+
+	if e != nil {
+		return nil, e
+	}
+
+	if len(exceptions) > 0 {
+		return &d, errors.Join(exceptions...)
+	}
+
+	return &d, nil
+}
+
+// Equal reports whether two units are equal in meaning: their sections are compared after normalization (see [Normalize]), disregarding
+// spelling (e.g. "yes" and "true"), time span and size formats, and duplicate list entries. The units' identity (Name, Kind and Source) and
+// history (DropIns and Provenance) are disregarded. Values that can't be normalized are compared as-is.
+func Equal(a, b Daemon) bool {
+	sections := func(d Daemon) []any {
+		if normalized, _ := Normalize(d); normalized != nil {
+			d = *normalized
+		}
+
+		v := make([]any, 0)
+		for _, tag := range reflection(reflect.ValueOf(&d).Elem()) {
+			v = append(v, tag.Value.Interface())
+		}
+
+		return v
+	}
+
+	return reflect.DeepEqual(sections(a), sections(b))
+}
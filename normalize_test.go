@@ -0,0 +1,208 @@
+package systemd_test
+
+import (
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("Normalize-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Description: "  Example  ",
+				After:       "network.target  remote-fs.target network.target",
+				Wants:       "b.service a.service",
+			},
+			Service: systemd.Service{
+				RemainAfterExit: "true",
+				RestartSec:      "90",
+				TimeoutStartSec: "1m30s",
+				Kill:            systemd.Kill{KillSignal: "term"},
+				Exec: systemd.Exec{
+					NoNewPrivileges: "0",
+					UMask:           "27",
+					Environment:     `A=1 "B=two words" A=1`,
+				},
+				ResourceControl: systemd.ResourceControl{
+					MemoryMax: "1024M",
+				},
+			},
+			Timer: &systemd.Timer{},
+		}
+
+		normalized, e := systemd.Normalize(daemon)
+		if e != nil {
+			t.Fatalf("unexpected error: %v", e)
+		}
+
+		cases := map[string][2]string{
+			"Description":     {normalized.Unit.Description, "Example"},
+			"After":           {normalized.Unit.After, "network.target remote-fs.target"},
+			"Wants":           {normalized.Unit.Wants, "a.service b.service"},
+			"RemainAfterExit": {normalized.Service.RemainAfterExit, "yes"},
+			"NoNewPrivileges": {normalized.Service.NoNewPrivileges, "no"},
+			"RestartSec":      {normalized.Service.RestartSec, "1min 30s"},
+			"TimeoutStartSec": {normalized.Service.TimeoutStartSec, "1min 30s"},
+			"MemoryMax":       {normalized.Service.MemoryMax, "1G"},
+			"UMask":           {normalized.Service.UMask, "0027"},
+			"Environment":     {normalized.Service.Environment, `A=1 "B=two words"`},
+			"KillSignal":      {normalized.Service.KillSignal.String(), "SIGTERM"},
+		}
+
+		for name, v := range cases {
+			if v[0] != v[1] {
+				t.Errorf("unexpected normalization of %s=: %q, expected %q", name, v[0], v[1])
+			}
+		}
+
+		if normalized.Timer != nil {
+			t.Errorf("expected the empty [Timer] section to be removed")
+		}
+
+		// The original Daemon is left unchanged.
+		if daemon.Service.RemainAfterExit != "true" {
+			t.Errorf("original daemon modified")
+		}
+	})
+
+	t.Run("Normalize-Timespan-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"0":            "0",
+			"infinity":     "infinity",
+			"100ms":        "100ms",
+			"1.5s":         "1s 500ms",
+			"2h 30min":     "2h 30min",
+			"150min":       "2h 30min",
+			"1 week 1 day": "1w 1d",
+			"5 min":        "5min",
+			"500us":        "500us",
+		}
+
+		for value, expectation := range cases {
+			normalized, e := systemd.Normalize(systemd.Daemon{Service: systemd.Service{RestartSec: value}})
+			if e != nil || normalized.Service.RestartSec != expectation {
+				t.Errorf("unexpected normalization of %q: %q (%v)", value, normalized.Service.RestartSec, e)
+			}
+		}
+	})
+
+	t.Run("Normalize-Nanosecond-Timespan-Test", func(t *testing.T) {
+		// Unitless TimerSlackNSec= values are nanoseconds, and aren't rounded to microseconds.
+		cases := map[string]string{
+			"50":        "50ns",
+			"50000":     "50us",
+			"1500 nsec": "1us 500ns",
+			"1ms":       "1ms",
+		}
+
+		for value, expectation := range cases {
+			normalized, e := systemd.Normalize(systemd.Daemon{Service: systemd.Service{Exec: systemd.Exec{TimerSlackNSec: value}}})
+			if e != nil || normalized.Service.TimerSlackNSec != expectation {
+				t.Errorf("unexpected normalization of %q: %q (%v)", value, normalized.Service.TimerSlackNSec, e)
+			}
+		}
+
+		// Other time spans are rounded to microseconds.
+		normalized, e := systemd.Normalize(systemd.Daemon{Service: systemd.Service{RestartSec: "1500ns"}})
+		if e != nil || normalized.Service.RestartSec != "2us" {
+			t.Errorf("unexpected normalization: %q (%v)", normalized.Service.RestartSec, e)
+		}
+	})
+
+	t.Run("Normalize-Environment-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"A=1 A=2 A=1":           "A=1",
+			"A=1 B=2 A=3":           "A=3 B=2",
+			`"A=two words" A=other`: "A=other",
+			`A=1 "B=x y" "B=z"`:     `A=1 B=z`,
+			`B="x y" 'C=1'`:         `"B=x y" C=1`,
+			`B="say 'hi'"`:          `"B=say 'hi'"`,
+			`'B=say "hi"'`:          `'B=say "hi"'`,
+		}
+
+		for value, expectation := range cases {
+			normalized, e := systemd.Normalize(systemd.Daemon{Service: systemd.Service{Exec: systemd.Exec{Environment: value}}})
+			if e != nil || normalized.Service.Environment != expectation {
+				t.Errorf("unexpected normalization of %q: %q (%v)", value, normalized.Service.Environment, e)
+			}
+		}
+	})
+
+	t.Run("Normalize-Invalid-Test", func(t *testing.T) {
+		normalized, e := systemd.Normalize(systemd.Daemon{Service: systemd.Service{RemainAfterExit: "maybe", RestartSec: "5 fortnights", ResourceControl: systemd.ResourceControl{MemoryMax: "2G"}}})
+		if e == nil {
+			t.Fatalf("expected an error for invalid values")
+		}
+
+		if normalized == nil || normalized.Service.RemainAfterExit != "maybe" || normalized.Service.MemoryMax != "2G" {
+			t.Errorf("unexpected normalization: %+v", normalized)
+		}
+	})
+}
+
+func TestEqual(t *testing.T) {
+	a := systemd.Daemon{
+		Name: "example.service",
+		Unit: systemd.Unit{After: "network.target remote-fs.target"},
+		Service: systemd.Service{
+			ExecStart:       []string{"/usr/bin/example"},
+			RemainAfterExit: "yes",
+			TimeoutStartSec: "90",
+			ResourceControl: systemd.ResourceControl{
+				MemoryMax: "1G",
+			},
+		},
+	}
+
+	t.Run("Equal-Test", func(t *testing.T) {
+		b := a
+		b.Name, b.Source = "", "/etc/systemd/system/example.service"
+		b.Unit.After = "remote-fs.target network.target network.target"
+		b.Service.RemainAfterExit = "true"
+		b.Service.TimeoutStartSec = "1min 30s"
+		b.Service.MemoryMax = "1024M"
+		b.Timer = &systemd.Timer{}
+
+		if !(systemd.Equal(a, b)) {
+			t.Errorf("expected units to be equal")
+		}
+	})
+
+	t.Run("Equal-Environment-Quoting-Test", func(t *testing.T) {
+		b, c := a, a
+		b.Service.Environment = `A=1 B="x y"`
+		c.Service.Environment = `"A=1" "B=x y"`
+
+		if !(systemd.Equal(b, c)) {
+			t.Errorf("expected environments differing in quoting to be equal")
+		}
+	})
+
+	t.Run("Not-Equal-Test", func(t *testing.T) {
+		b := a
+		b.Service.TimeoutStartSec = "2min"
+
+		if systemd.Equal(a, b) {
+			t.Errorf("expected units to differ")
+		}
+
+		c := a
+		c.Service.ExecStart = []string{"/usr/bin/example", "/usr/bin/example"}
+
+		if systemd.Equal(a, c) {
+			t.Errorf("expected units with different commands to differ")
+		}
+	})
+
+	t.Run("Equal-Unmarshal-Test", func(t *testing.T) {
+		instance, e := systemd.Unmarshal([]byte("[Unit]\nAfter=remote-fs.target\nAfter=network.target\n\n[Service]\nTimeoutStartSec=1.5min\nExecStart=/usr/bin/example\nMemoryMax=1073741824\nRemainAfterExit=on\n"))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if !(systemd.Equal(a, *instance)) {
+			t.Errorf("expected the unmarshalled unit to equal the Daemon")
+		}
+	})
+}
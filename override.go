@@ -0,0 +1,159 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// section returns the section structure of a Daemon's section tag, allocating a zero-value structure for nil pointer sections.
+func (t *tag) section() reflect.Value {
+	if t.Value.Kind() != reflect.Pointer {
+		return t.Value
+	}
+
+	if t.Value.IsNil() {
+		return reflect.New(t.Value.Type().Elem()).Elem()
+	}
+
+	return t.Value.Elem()
+}
+
+// words returns the space-separated words of a list directive's single assignment, if any. Quoted words (e.g. Environment="A=b c") are kept intact.
+func words(values []entry) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return tokens(values[0].Value)
+}
+
+// extends reports whether the desired list starts with every element of the base list, returning the remaining (appended) elements.
+func extends[T comparable](base, desired []T) ([]T, bool) {
+	if len(desired) < len(base) {
+		return nil, false
+	}
+
+	for idx := range base {
+		if base[idx] != desired[idx] {
+			return nil, false
+		}
+	}
+
+	return desired[len(base):], true
+}
+
+// difference returns the assignments a drop-in requires to change a single field from the base to the desired values:
+//
+//   - Scalar directives are assigned the desired value, or reset with an empty assignment if the desired value is empty.
+//   - List directives (repeatable slice fields, and space-separated list directives per the [Directive] registry) that extend the base list are
+//     appended to; otherwise, the list is reset with an empty assignment, and every desired element assigned.
+//
+// An error is returned for lists that can't be reset; e.g. dependencies such as After=, which drop-ins can only add to.
+func difference(section string, base, desired *tag) ([]entry, error) {
+	b, e := base.values()
+	if e != nil {
+		return nil, e
+	}
+
+	d, e := desired.values()
+	if e != nil {
+		return nil, e
+	}
+
+	if reflect.DeepEqual(b, d) {
+		return nil, nil
+	}
+
+	directive, registered := Lookup(section, desired.Name)
+	resettable := !(registered) || directive.Resettable || desired.Prefix
+
+	if desired.Value.Kind() == reflect.Slice {
+		if appended, ok := extends(b, d); ok {
+			return appended, nil
+		}
+
+		if !(resettable) {
+			return nil, fmt.Errorf("[%s] %s= can't be reset by a drop-in", section, desired.Name)
+		}
+
+		key := desired.Name
+		switch {
+		case len(d) > 0:
+			key = d[0].Key
+		case len(b) > 0:
+			key = b[0].Key
+		}
+
+		return append([]entry{{Key: key}}, d...), nil
+	}
+
+	if registered && directive.Repeatable && directive.Type.List() {
+		if appended, ok := extends(words(b), words(d)); ok {
+			return []entry{{Key: desired.Name, Value: strings.Join(appended, " ")}}, nil
+		}
+
+		if !(resettable) {
+			return nil, fmt.Errorf("[%s] %s= can't be reset by a drop-in", section, desired.Name)
+		}
+
+		if len(d) == 0 {
+			return []entry{{Key: desired.Name}}, nil
+		}
+
+		return []entry{{Key: desired.Name}, d[0]}, nil
+	}
+
+	if len(d) == 0 {
+		return []entry{{Key: desired.Name}}, nil
+	}
+
+	return d, nil
+}
+
+// Override returns the smallest drop-in (e.g. "/etc/systemd/system/example.service.d/override.conf") that, applied on top of the base unit, results
+// in the desired unit - the programmatic equivalent of systemctl edit. Directives that are equal in both units are omitted; see [Merge] for
+// reconciling concurrent changes instead.
+//
+//   - Changed scalar directives are assigned their desired value, or reset to their default with an empty assignment (e.g. "User=").
+//   - List directives are appended to where the desired list extends the base list; otherwise, the list is reset with an empty assignment (e.g.
+//     "ExecStart=") prior to assigning the desired elements.
+//
+// An error is returned if the desired unit can't be reached by a drop-in, such as for removed dependencies (e.g. After=), which can't be reset.
+// An empty drop-in is returned if both units are equal.
+func Override(base, desired Daemon) ([]byte, error) {
+	var exceptions = make([]error, 0)
+
+	file := &document{Groups: make([]*group, 0)}
+
+	b := reflection(reflect.ValueOf(&base).Elem())
+	d := reflection(reflect.ValueOf(&desired).Elem())
+	for idx := range d {
+		name := d[idx].Name
+
+		bases := reflection(b[idx].section())
+		desires := reflection(d[idx].section())
+
+		entries := make([]entry, 0)
+		for field := range desires {
+			v, e := difference(name, bases[field], desires[field])
+			if e != nil {
+				exceptions = append(exceptions, e)
+				continue
+			}
+
+			entries = append(entries, v...)
+		}
+
+		if len(entries) > 0 {
+			file.Groups = append(file.Groups, &group{Name: name, Entries: entries})
+		}
+	}
+
+	if len(exceptions) > 0 {
+		return nil, errors.Join(exceptions...)
+	}
+
+	return file.bytes(), nil
+}
@@ -0,0 +1,137 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestOverride(t *testing.T) {
+	base := systemd.Daemon{
+		Unit: systemd.Unit{
+			Description: "Vendor Agent",
+			After:       "network.target",
+		},
+		Service: systemd.Service{
+			ExecStart:    []string{"/usr/bin/agent"},
+			ExecStartPre: []string{"/usr/bin/agent --check"},
+			Exec: systemd.Exec{
+				User:        "agent",
+				Environment: "MODE=production",
+			},
+		},
+		Install: systemd.Install{
+			WantedBy: "multi-user.target",
+		},
+	}
+
+	t.Run("Override-Minimal-Test", func(t *testing.T) {
+		desired := base
+		desired.Unit.After = "network.target remote-fs.target"
+		desired.Service.ExecStart = []string{"/usr/bin/agent --verbose"}
+		desired.Service.ExecStartPre = []string{"/usr/bin/agent --check", "/usr/bin/agent --migrate"}
+		desired.Service.User = ""
+		desired.Service.MemoryMax = "1G"
+
+		content, e := systemd.Override(base, desired)
+		if e != nil {
+			t.Fatalf("failed generating override: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"After=remote-fs.target",
+			"",
+			"[Service]",
+			"ExecStart=",
+			"ExecStart=/usr/bin/agent --verbose",
+			"ExecStartPre=/usr/bin/agent --migrate",
+			"User=",
+			"MemoryMax=1G",
+			"",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected override:\n%s", string(content))
+		}
+
+		// Applying the override on top of the base unit must result in the desired unit.
+		original, e := systemd.Marshal(base)
+		if e != nil {
+			t.Fatalf("failed marshalling base: %v", e)
+		}
+
+		instance, e := systemd.Unmarshal(append(append(original, '\n'), content...))
+		if e != nil {
+			t.Fatalf("failed unmarshalling overridden unit: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Unit, desired.Unit)) || !(reflect.DeepEqual(instance.Service, desired.Service)) {
+			t.Errorf("unexpected overridden unit: %+v", instance)
+		}
+	})
+
+	t.Run("Override-Reset-List-Test", func(t *testing.T) {
+		desired := base
+		desired.Service.ExecStartPre = nil
+		desired.Service.Environment = "MODE=staging"
+
+		content, e := systemd.Override(base, desired)
+		if e != nil {
+			t.Fatalf("failed generating override: %v", e)
+		}
+
+		expectation := "[Service]\nExecStartPre=\nEnvironment=\nEnvironment=MODE=staging\n"
+		if string(content) != expectation {
+			t.Errorf("unexpected override:\n%s", string(content))
+		}
+	})
+
+	t.Run("Override-Quoted-List-Test", func(t *testing.T) {
+		quoted := base
+		quoted.Service.Environment = `"GREETING=hello  world"`
+
+		desired := quoted
+		desired.Service.Environment = `"GREETING=hello world" MODE=staging`
+
+		content, e := systemd.Override(quoted, desired)
+		if e != nil {
+			t.Fatalf("failed generating override: %v", e)
+		}
+
+		// The quoted assignment differs in its whitespace, and as such, can't be appended to.
+		expectation := "[Service]\nEnvironment=\nEnvironment=\"GREETING=hello world\" MODE=staging\n"
+		if string(content) != expectation {
+			t.Errorf("unexpected override:\n%s", string(content))
+		}
+
+		desired.Service.Environment = `"GREETING=hello  world" "MODE=staging area"`
+
+		content, e = systemd.Override(quoted, desired)
+		if e != nil {
+			t.Fatalf("failed generating override: %v", e)
+		}
+
+		if expectation := "[Service]\nEnvironment=\"MODE=staging area\"\n"; string(content) != expectation {
+			t.Errorf("unexpected override:\n%s", string(content))
+		}
+	})
+
+	t.Run("Override-Equal-Test", func(t *testing.T) {
+		content, e := systemd.Override(base, base)
+		if e != nil || len(content) != 0 {
+			t.Errorf("expected an empty override for equal units: %q (%v)", string(content), e)
+		}
+	})
+
+	t.Run("Override-Dependency-Removal-Test", func(t *testing.T) {
+		desired := base
+		desired.Unit.After = "remote-fs.target"
+
+		if _, e := systemd.Override(base, desired); e == nil {
+			t.Errorf("expected an error for a removed dependency")
+		}
+	})
+}
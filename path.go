@@ -0,0 +1,25 @@
+package systemd
+
+// Path represents the [Path] section of a systemd path file.
+//
+// A path unit (e.g. "example.path") monitors file system paths via inotify(7), and activates another unit, by default the service unit of the
+// same name (e.g. "example.service"), when a watched path changes.
+//
+//   - PathExists, PathExistsGlob, PathChanged, PathModified and DirectoryNotEmpty may each be specified more than once; the unit is activated if
+//     any of the watched conditions apply. An empty assignment resets the respective list.
+//
+// See [systemd.path] for additional details.
+//
+// [systemd.path]: https://www.freedesktop.org/software/systemd/man/latest/systemd.path.html
+type Path struct {
+	PathExists              []string `json:"PathExists,omitempty" yaml:"PathExists,omitempty" ini:"-" systemd:"PathExists,omitempty"`                                                                        // Activates the unit when the specified absolute path exists.
+	PathExistsGlob          []string `json:"PathExistsGlob,omitempty" yaml:"PathExistsGlob,omitempty" ini:"-" systemd:"PathExistsGlob,omitempty"`                                                            // Activates the unit when at least one file matching the specified glob pattern exists.
+	PathChanged             []string `json:"PathChanged,omitempty" yaml:"PathChanged,omitempty" ini:"-" systemd:"PathChanged,omitempty"`                                                                     // Activates the unit when the specified file is closed after being written to, or is renamed or removed.
+	PathModified            []string `json:"PathModified,omitempty" yaml:"PathModified,omitempty" ini:"-" systemd:"PathModified,omitempty"`                                                                  // Similar to PathChanged, but additionally activates the unit on every individual write to the file.
+	DirectoryNotEmpty       []string `json:"DirectoryNotEmpty,omitempty" yaml:"DirectoryNotEmpty,omitempty" ini:"-" systemd:"DirectoryNotEmpty,omitempty"`                                                   // Activates the unit when the specified directory contains at least one file.
+	Unit                    string   `json:"Unit,omitempty" yaml:"Unit,omitempty" ini:"Unit,omitempty" systemd:"Unit,omitempty"`                                                                             // Specifies the unit to activate. Defaults to a service with the same name as the path unit, except for the suffix.
+	MakeDirectory           string   `json:"MakeDirectory,omitempty" yaml:"MakeDirectory,omitempty" ini:"MakeDirectory,omitempty" systemd:"MakeDirectory,omitempty"`                                         // If true, the directories to watch are created before watching. Applies to DirectoryNotEmpty only.
+	DirectoryMode           string   `json:"DirectoryMode,omitempty" yaml:"DirectoryMode,omitempty" ini:"DirectoryMode,omitempty" systemd:"DirectoryMode,omitempty"`                                         // Specifies the access mode of directories created by MakeDirectory. Defaults to 0755.
+	TriggerLimitIntervalSec string   `json:"TriggerLimitIntervalSec,omitempty" yaml:"TriggerLimitIntervalSec,omitempty" ini:"TriggerLimitIntervalSec,omitempty" systemd:"TriggerLimitIntervalSec,omitempty"` // Configures rate limiting for activation requests. See related TriggerLimitBurst
+	TriggerLimitBurst       string   `json:"TriggerLimitBurst,omitempty" yaml:"TriggerLimitBurst,omitempty" ini:"TriggerLimitBurst,omitempty" systemd:"TriggerLimitBurst,omitempty"`                         // Configures rate limiting for activation requests. See related TriggerLimitIntervalSec
+}
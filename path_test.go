@@ -0,0 +1,69 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+// [Unit]
+// Description=Ingestion Trigger
+//
+// [Path]
+// PathExistsGlob=/srv/ingest/*.csv
+// DirectoryNotEmpty=/srv/ingest/incoming
+// Unit=ingest.service
+// MakeDirectory=yes
+//
+// [Install]
+// WantedBy=paths.target
+func TestPath(t *testing.T) {
+	daemon := systemd.Daemon{
+		Unit: systemd.Unit{
+			Description: "Ingestion Trigger",
+		},
+		Install: systemd.Install{
+			WantedBy: "paths.target",
+		},
+		Path: &systemd.Path{
+			PathExistsGlob:    []string{"/srv/ingest/*.csv"},
+			DirectoryNotEmpty: []string{"/srv/ingest/incoming"},
+			Unit:              "ingest.service",
+			MakeDirectory:     "yes",
+		},
+	}
+
+	content, e := systemd.Marshal(daemon)
+	if e != nil {
+		t.Fatalf("failed marshalling daemon: %v", e)
+	}
+
+	expectation := strings.Join([]string{
+		"[Unit]",
+		"Description=Ingestion Trigger",
+		"",
+		"[Path]",
+		"PathExistsGlob=/srv/ingest/*.csv",
+		"DirectoryNotEmpty=/srv/ingest/incoming",
+		"Unit=ingest.service",
+		"MakeDirectory=yes",
+		"",
+		"[Install]",
+		"WantedBy=paths.target",
+	}, "\n")
+
+	if string(content) != expectation {
+		t.Errorf("unexpected marshalled output:\n%s", string(content))
+	}
+
+	instance, e := systemd.Unmarshal(content)
+	if e != nil {
+		t.Fatalf("failed unmarshalling daemon: %v", e)
+	}
+
+	if !(reflect.DeepEqual(instance.Path, daemon.Path)) {
+		t.Errorf("unexpected path after round-trip: %+v", instance.Path)
+	}
+}
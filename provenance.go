@@ -0,0 +1,279 @@
+package systemd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Origin represents where a directive's value came from.
+type Origin string
+
+const (
+	OriginFragment Origin = "fragment" // The value was assigned by the unit's main file (fragment).
+	OriginDropIn   Origin = "drop-in"  // The value was assigned by a drop-in, overriding or extending the fragment.
+	OriginDefault  Origin = "default"  // The directive wasn't assigned; the value is systemd's built-in default.
+	OriginReset    Origin = "reset"    // The directive was reset by an empty assignment, e.g. "ExecStart=".
+)
+
+// Provenance represents a single assignment of a directive, along with the file and line it was read from.
+//
+//   - File is empty for units decoded from memory (see [Unmarshal]), and for built-in defaults.
+//   - Line is the 1-based line the assignment starts on, or 0 for built-in defaults.
+//   - Ignored reports an empty assignment systemd disregards, as the directive can't be reset - e.g. "After=". See [Directive.Resettable].
+//   - Section and Directive name the directive as it's decoded; legacy placements, e.g. StartLimitInterval= in [Service], are recorded as
+//     StartLimitIntervalSec= in [Unit], with Placement set to "Service".
+type Provenance struct {
+	Section   string `json:"Section" yaml:"Section"`                         // Specifies the section the directive was assigned in, e.g. "Service".
+	Directive string `json:"Directive" yaml:"Directive"`                     // Specifies the directive's key, e.g. "TimeoutStopSec".
+	Value     string `json:"Value" yaml:"Value"`                             // Specifies the assigned value; empty for resets.
+	File      string `json:"File,omitempty" yaml:"File,omitempty"`           // Specifies the file system path of the file the directive was assigned in.
+	Line      int    `json:"Line,omitempty" yaml:"Line,omitempty"`           // Specifies the line the assignment starts on.
+	Origin    Origin `json:"Origin" yaml:"Origin"`                           // Specifies whether the value came from the fragment, a drop-in, a default, or a reset.
+	Ignored   bool   `json:"Ignored,omitempty" yaml:"Ignored,omitempty"`     // Reports whether systemd disregards the assignment.
+	Placement string `json:"Placement,omitempty" yaml:"Placement,omitempty"` // Specifies the section the directive was written in, if systemd reads it into another, e.g. "Service" for a StartLimitBurst= in [Service].
+}
+
+// String returns the provenance's location in the "file:line" form, e.g. "/etc/systemd/system/agent.service.d/10-timeout.conf:2".
+func (p Provenance) String() string {
+	switch {
+	case p.Origin == OriginDefault:
+		return "(default)"
+	case p.File == "":
+		return fmt.Sprintf("line %d", p.Line)
+	}
+
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// defaults specifies systemd's built-in defaults of commonly queried directives, as documented by [systemd.service], [systemd.unit],
+// [systemd.kill], [systemd.socket] and [systemd.timer]. Defaults configurable through systemd-system.conf(5) are given as shipped.
+//
+// [systemd.service]: https://www.freedesktop.org/software/systemd/man/latest/systemd.service.html
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html
+// [systemd.kill]: https://www.freedesktop.org/software/systemd/man/latest/systemd.kill.html
+// [systemd.socket]: https://www.freedesktop.org/software/systemd/man/latest/systemd.socket.html
+// [systemd.timer]: https://www.freedesktop.org/software/systemd/man/latest/systemd.timer.html
+var defaults = map[string]map[string]string{
+	"Unit": {
+		"DefaultDependencies":   "yes",
+		"StartLimitIntervalSec": "10s",
+		"StartLimitBurst":       "5",
+		"StopWhenUnneeded":      "no",
+		"RefuseManualStart":     "no",
+		"RefuseManualStop":      "no",
+	},
+	"Service": {
+		"Type":            "simple",
+		"Restart":         "no",
+		"RestartSec":      "100ms",
+		"TimeoutStartSec": "90s",
+		"TimeoutStopSec":  "90s",
+		"RemainAfterExit": "no",
+		"KillMode":        "control-group",
+		"KillSignal":      "SIGTERM",
+		"SendSIGKILL":     "yes",
+		"SendSIGHUP":      "no",
+		"UMask":           "0022",
+		"NoNewPrivileges": "no",
+		"StandardInput":   "null",
+		"StandardOutput":  "journal",
+		"StandardError":   "inherit",
+		"OOMPolicy":       "stop",
+	},
+	"Socket": {
+		"Accept":       "no",
+		"Backlog":      "4096",
+		"SocketMode":   "0666",
+		"RemoveOnStop": "no",
+	},
+	"Timer": {
+		"AccuracySec":       "1min",
+		"Persistent":        "no",
+		"WakeSystem":        "no",
+		"RemainAfterElapse": "yes",
+	},
+}
+
+// attribute records the given file and origin on each of the document's assignments.
+func (d *document) attribute(file string, origin Origin) {
+	for _, g := range d.Groups {
+		for idx := range g.Entries {
+			g.Entries[idx].File, g.Entries[idx].Origin = file, origin
+		}
+	}
+}
+
+// provenance returns the provenance of each of the document's assignments, in the order they're applied. Assignments without a recorded origin are
+// attributed to the fragment.
+func (d *document) provenance() []Provenance {
+	provenance := make([]Provenance, 0)
+	for _, g := range d.Groups {
+		for _, assignment := range g.Entries {
+			p := Provenance{Section: g.Name, Directive: assignment.Key, Value: assignment.Value, File: assignment.File, Line: assignment.Line, Origin: assignment.Origin}
+			if p.Origin == "" {
+				p.Origin = OriginFragment
+			}
+
+			for _, relocation := range relocations {
+				if relocation.From == g.Name && relocation.Key == assignment.Key {
+					p.Section, p.Directive, p.Placement = relocation.To, relocation.Replacement, g.Name
+					break
+				}
+			}
+
+			if assignment.Value == "" {
+				p.Origin = OriginReset
+				if directive, ok := Lookup(p.Section, p.Directive); ok && directive.Repeatable && directive.Type.List() && !(directive.Resettable) {
+					p.Ignored = true
+				}
+			}
+
+			provenance = append(provenance, p)
+		}
+	}
+
+	return provenance
+}
+
+// Trace returns every assignment of the given section's directive, in the order they're applied - e.g. an assignment in the fragment, followed by an
+// override of a drop-in. Assignments of directives that accumulate (e.g. ExecStart=) all contribute to the effective value, up to the last reset.
+// Trace returns nil if the directive wasn't assigned. See [Daemon.Explain] for the assignment determining the effective value.
+func (d Daemon) Trace(section, directive string) []Provenance {
+	var trace []Provenance
+	for _, p := range d.Provenance {
+		if p.Section == section && p.Directive == directive {
+			trace = append(trace, p)
+		}
+	}
+
+	return trace
+}
+
+// Explain answers why a directive has its effective value: it returns the last assignment of the given section's directive systemd honors - an
+// assignment, or a reset. For directives that accumulate, earlier assignments following the last reset contribute as well; see [Daemon.Trace].
+//
+// If the directive wasn't assigned, Explain returns systemd's built-in default where known, with an [OriginDefault] origin. Explain reports false
+// if the directive wasn't assigned and has no known default.
+func (d Daemon) Explain(section, directive string) (Provenance, bool) {
+	trace := d.Trace(section, directive)
+	for idx := len(trace) - 1; idx >= 0; idx-- {
+		if !(trace[idx].Ignored) {
+			return trace[idx], true
+		}
+	}
+
+	if value, ok := defaults[section][directive]; ok {
+		return Provenance{Section: section, Directive: directive, Value: value, Origin: OriginDefault}, true
+	}
+
+	return Provenance{}, false
+}
+
+// accumulates reports whether repeated assignments of the directive add to, rather than replace, its value.
+func accumulates(section, directive string) bool {
+	if v, ok := Lookup(section, directive); ok {
+		return v.Repeatable
+	}
+
+	return strings.HasPrefix(directive, "Condition") || strings.HasPrefix(directive, "Assert")
+}
+
+// annotation returns a comment describing why the assignment at the given index of the Daemon's provenance isn't (entirely) effective, or an
+// empty string if it is.
+func (d Daemon) annotation(index int) string {
+	p := d.Provenance[index]
+	if p.Ignored {
+		return fmt.Sprintf("# ignored: %s= can't be reset", p.Directive)
+	}
+
+	list := accumulates(p.Section, p.Directive)
+	for _, later := range d.Provenance[index+1:] {
+		if later.Section != p.Section || later.Directive != p.Directive || later.Ignored {
+			continue
+		}
+
+		switch {
+		case later.Origin == OriginReset:
+			return fmt.Sprintf("# reset by %s", later)
+		case !(list) && p.Origin != OriginReset:
+			return fmt.Sprintf("# overridden by %s", later)
+		}
+	}
+
+	return ""
+}
+
+// Cat returns the Daemon's unit file and drop-ins in the form of systemctl cat - each file's assignments, in order, following a "# path" header -
+// annotated with comments for assignments that are overridden, reset, or ignored by later ones. For example:
+//
+//	# /usr/lib/systemd/system/agent.service
+//	[Service]
+//	TimeoutStopSec=90s
+//	# overridden by /etc/systemd/system/agent.service.d/10-timeout.conf:2
+//
+//	# /etc/systemd/system/agent.service.d/10-timeout.conf
+//	[Service]
+//	TimeoutStopSec=5min
+//
+// Cat renders the decoded assignments rather than the files' verbatim content, so comments and line continuations aren't reproduced. See [Load],
+// [LoadEffective] and [Resolver.Load] for loading units along with their provenance.
+func (d Daemon) Cat() string {
+	files := make([]string, 0)
+	assignments := make(map[string][]int)
+	for idx, p := range d.Provenance {
+		if _, ok := assignments[p.File]; !(ok) {
+			files = append(files, p.File)
+		}
+
+		assignments[p.File] = append(assignments[p.File], idx)
+	}
+
+	// Files are listed in the order they're applied: the fragment, followed by the drop-ins.
+	order := append([]string{d.Source}, d.DropIns...)
+	rank := func(file string) int {
+		for idx, candidate := range order {
+			if candidate == file {
+				return idx
+			}
+		}
+
+		return len(order)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return rank(files[i]) < rank(files[j])
+	})
+
+	var builder strings.Builder
+	for n, file := range files {
+		if n > 0 {
+			builder.WriteString("\n")
+		}
+
+		if file != "" {
+			builder.WriteString("# " + file + "\n")
+		}
+
+		indices := assignments[file]
+		sort.SliceStable(indices, func(i, j int) bool {
+			return d.Provenance[indices[i]].Line < d.Provenance[indices[j]].Line
+		})
+
+		section := ""
+		for _, idx := range indices {
+			p := d.Provenance[idx]
+			if p.Section != section {
+				section = p.Section
+				builder.WriteString("[" + section + "]\n")
+			}
+
+			builder.WriteString(p.Directive + "=" + p.Value + "\n")
+			if annotation := d.annotation(idx); annotation != "" {
+				builder.WriteString(annotation + "\n")
+			}
+		}
+	}
+
+	return builder.String()
+}
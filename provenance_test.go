@@ -0,0 +1,149 @@
+package systemd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestProvenance(t *testing.T) {
+	directory := t.TempDir()
+
+	files := map[string]string{
+		"agent.service":                   "[Unit]\nDescription=Agent\nAfter=network.target\n\n[Service]\nExecStart=/usr/bin/agent\nTimeoutStopSec=90s\nUser=agent\n",
+		"agent.service.d/10-timeout.conf": "[Service]\nTimeoutStopSec=5min\n",
+		"agent.service.d/20-exec.conf":    "# Replaces the vendor's command.\n[Unit]\nAfter=\n\n[Service]\nExecStart=\nExecStart=/usr/bin/agent --verbose\nUser=\n",
+	}
+
+	for name, content := range files {
+		p := filepath.Join(directory, name)
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.WriteFile(p, []byte(content), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	fragment := filepath.Join(directory, "agent.service")
+	timeout := filepath.Join(directory, "agent.service.d", "10-timeout.conf")
+	exec := filepath.Join(directory, "agent.service.d", "20-exec.conf")
+
+	instance, e := systemd.LoadEffective(fragment)
+	if e != nil {
+		t.Fatalf("failed loading unit: %v", e)
+	}
+
+	t.Run("Explain-Drop-In-Test", func(t *testing.T) {
+		p, ok := instance.Explain("Service", "TimeoutStopSec")
+		if !(ok) || p.Value != "5min" || p.File != timeout || p.Line != 2 || p.Origin != systemd.OriginDropIn {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+
+		if trace := instance.Trace("Service", "TimeoutStopSec"); len(trace) != 2 || trace[0].File != fragment || trace[0].Line != 7 || trace[0].Origin != systemd.OriginFragment {
+			t.Errorf("unexpected trace: %+v", trace)
+		}
+	})
+
+	t.Run("Explain-Reset-Test", func(t *testing.T) {
+		p, ok := instance.Explain("Service", "User")
+		if !(ok) || p.Origin != systemd.OriginReset || p.File != exec || p.Line != 8 {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+	})
+
+	t.Run("Explain-Ignored-Reset-Test", func(t *testing.T) {
+		p, ok := instance.Explain("Unit", "After")
+		if !(ok) || p.Origin != systemd.OriginFragment || p.Value != "network.target" {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+
+		if trace := instance.Trace("Unit", "After"); len(trace) != 2 || !(trace[1].Ignored) {
+			t.Errorf("unexpected trace: %+v", trace)
+		}
+	})
+
+	t.Run("Explain-Default-Test", func(t *testing.T) {
+		p, ok := instance.Explain("Service", "Restart")
+		if !(ok) || p.Origin != systemd.OriginDefault || p.Value != "no" || p.String() != "(default)" {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+
+		if _, ok := instance.Explain("Service", "WorkingDirectory"); ok {
+			t.Errorf("expected no provenance for an unassigned directive without a default")
+		}
+	})
+
+	t.Run("Explain-Relocated-Test", func(t *testing.T) {
+		instance, e := systemd.Unmarshal([]byte("[Unit]\nStartLimitBurst=3\n\n[Service]\nExecStart=/usr/bin/agent\nStartLimitBurst=10\nStartLimitInterval=30s\n"))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		p, ok := instance.Explain("Unit", "StartLimitBurst")
+		if !(ok) || p.Value != "10" || p.Line != 6 || p.Placement != "Service" || p.Origin != systemd.OriginFragment {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+
+		if trace := instance.Trace("Unit", "StartLimitBurst"); len(trace) != 2 || trace[0].Line != 2 || trace[0].Placement != "" {
+			t.Errorf("unexpected trace: %+v", trace)
+		}
+
+		if p, ok := instance.Explain("Unit", "StartLimitIntervalSec"); !(ok) || p.Value != "30s" || p.Placement != "Service" {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+
+		if trace := instance.Trace("Service", "StartLimitBurst"); len(trace) != 0 {
+			t.Errorf("unexpected trace: %+v", trace)
+		}
+	})
+
+	t.Run("Cat-Test", func(t *testing.T) {
+		expectation := strings.Join([]string{
+			"# " + fragment,
+			"[Unit]",
+			"Description=Agent",
+			"After=network.target",
+			"[Service]",
+			"ExecStart=/usr/bin/agent",
+			"# reset by " + exec + ":6",
+			"TimeoutStopSec=90s",
+			"# overridden by " + timeout + ":2",
+			"User=agent",
+			"# reset by " + exec + ":8",
+			"",
+			"# " + timeout,
+			"[Service]",
+			"TimeoutStopSec=5min",
+			"",
+			"# " + exec,
+			"[Unit]",
+			"After=",
+			"# ignored: After= can't be reset",
+			"[Service]",
+			"ExecStart=",
+			"ExecStart=/usr/bin/agent --verbose",
+			"User=",
+			"",
+		}, "\n")
+
+		if output := instance.Cat(); output != expectation {
+			t.Errorf("unexpected output:\n%s", output)
+		}
+	})
+
+	t.Run("Unmarshal-Provenance-Test", func(t *testing.T) {
+		instance, e := systemd.Unmarshal([]byte("[Service]\nType=oneshot\n"))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if p, ok := instance.Explain("Service", "Type"); !(ok) || p.Origin != systemd.OriginFragment || p.File != "" || p.String() != "line 2" {
+			t.Errorf("unexpected provenance: %+v", p)
+		}
+	})
+}
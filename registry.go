@@ -0,0 +1,178 @@
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValueType represents the kind of value a directive accepts.
+type ValueType string
+
+const (
+	TypeString      ValueType = "string"      // Free-form string.
+	TypeBoolean     ValueType = "boolean"     // One of "yes", "no", "true", "false", "on", "off", "1", "0".
+	TypeInteger     ValueType = "integer"     // Decimal integer.
+	TypeTimespan    ValueType = "timespan"    // Time span, e.g. "90", "1min 30s", "infinity".
+	TypeSize        ValueType = "size"        // Byte size with optional K, M, G, T suffix, a percentage, or "infinity".
+	TypePercent     ValueType = "percent"     // Percentage, e.g. "20%".
+	TypeMode        ValueType = "mode"        // Octal access mode, e.g. "0755".
+	TypePath        ValueType = "path"        // Absolute file system path.
+	TypePaths       ValueType = "paths"       // Space-separated list of file system paths.
+	TypeUnits       ValueType = "units"       // Space-separated list of unit names.
+	TypeList        ValueType = "list"        // Space-separated list of words.
+	TypeEnum        ValueType = "enum"        // One of a fixed set of values.
+	TypeSignal      ValueType = "signal"      // Signal name or number.
+	TypeLimit       ValueType = "limit"       // Resource limit, optionally as "soft:hard".
+	TypeCommand     ValueType = "command"     // Command line, optionally with special executable prefixes.
+	TypeEnvironment ValueType = "environment" // Space-separated list of quoted VAR=VALUE assignments.
+	TypeCalendar    ValueType = "calendar"    // Calendar event expression, e.g. "Mon *-*-* 00:00:00".
+	TypeCondition   ValueType = "condition"   // Condition or assertion, optionally with "|" and "!" prefixes.
+	TypeAddress     ValueType = "address"     // Socket listening address.
+	TypeCredential  ValueType = "credential"  // Credential identifier and source.
+)
+
+// List reports whether values of the type are space-separated lists, where repeated assignments accumulate into a single list.
+func (v ValueType) List() bool {
+	switch v {
+	case TypePaths, TypeUnits, TypeList, TypeEnvironment:
+		return true
+	}
+
+	return false
+}
+
+// Directive represents the metadata of a single configuration directive, as documented by systemd.directives(7).
+type Directive struct {
+	Name          string    `json:"Name" yaml:"Name"`                                       // Specifies the directive's key, e.g. "ExecStart".
+	Sections      []string  `json:"Sections" yaml:"Sections"`                               // Specifies the section(s) the directive may be used in, e.g. "Service".
+	Type          ValueType `json:"Type" yaml:"Type"`                                       // Specifies the kind of value the directive accepts.
+	DefaultUnit   string    `json:"DefaultUnit,omitempty" yaml:"DefaultUnit,omitempty"`     // Specifies the unit of unitless time span values, e.g. "ns" for TimerSlackNSec=; empty for seconds.
+	Repeatable    bool      `json:"Repeatable,omitempty" yaml:"Repeatable,omitempty"`       // Reports whether the directive may be assigned more than once, accumulating values.
+	Resettable    bool      `json:"Resettable,omitempty" yaml:"Resettable,omitempty"`       // Reports whether an empty assignment resets any previously assigned values.
+	Since         int       `json:"Since" yaml:"Since"`                                     // Specifies the systemd version the directive was introduced in; 1 for directives predating versioned documentation.
+	Deprecated    int       `json:"Deprecated,omitempty" yaml:"Deprecated,omitempty"`       // Specifies the systemd version the directive was deprecated in, or 0.
+	Replacement   string    `json:"Replacement,omitempty" yaml:"Replacement,omitempty"`     // Specifies the directive superseding a deprecated directive, if any.
+	Documentation string    `json:"Documentation,omitempty" yaml:"Documentation,omitempty"` // Provides a short description of the directive.
+}
+
+// In reports whether the directive may be used in the given section.
+func (d Directive) In(section string) bool {
+	for _, s := range d.Sections {
+		if s == section {
+			return true
+		}
+	}
+
+	return false
+}
+
+//go:embed directives.tsv
+var directives []byte
+
+// registry represents the parsed, embedded directive registry.
+var registry struct {
+	once sync.Once
+
+	ordered []Directive
+	index   map[string]map[string]int // section -> name -> ordered index
+}
+
+// load parses the embedded directive registry. The registry is part of the package's source, so a malformed registry is a programming error.
+func load() {
+	registry.once.Do(func() {
+		registry.index = make(map[string]map[string]int)
+
+		scanner := bufio.NewScanner(bytes.NewReader(directives))
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+
+			directive, e := record(text)
+			if e != nil {
+				panic(fmt.Sprintf("invalid directive registry record on line %d: %v", line, e))
+			}
+
+			for _, section := range directive.Sections {
+				if registry.index[section] == nil {
+					registry.index[section] = make(map[string]int)
+				}
+
+				registry.index[section][directive.Name] = len(registry.ordered)
+			}
+
+			registry.ordered = append(registry.ordered, directive)
+		}
+	})
+}
+
+// record parses a single tab-separated registry line.
+func record(line string) (Directive, error) {
+	columns := strings.Split(line, "\t")
+	if len(columns) != 7 {
+		return Directive{}, fmt.Errorf("expected 7 columns, received %d", len(columns))
+	}
+
+	since, e := strconv.Atoi(columns[4])
+	if e != nil {
+		return Directive{}, fmt.Errorf("invalid since-version: %w", e)
+	}
+
+	kind, unit, _ := strings.Cut(columns[2], ":")
+
+	directive := Directive{
+		Name:          columns[0],
+		Sections:      strings.Split(columns[1], ","),
+		Type:          ValueType(kind),
+		DefaultUnit:   unit,
+		Repeatable:    strings.Contains(columns[3], "r"),
+		Resettable:    strings.Contains(columns[3], "e"),
+		Since:         since,
+		Documentation: columns[6],
+	}
+
+	if deprecation := columns[5]; deprecation != "-" {
+		version, replacement, _ := strings.Cut(deprecation, ":")
+		if directive.Deprecated, e = strconv.Atoi(version); e != nil {
+			return Directive{}, fmt.Errorf("invalid deprecation version: %w", e)
+		}
+
+		directive.Replacement = replacement
+	}
+
+	return directive, nil
+}
+
+// Directives returns every known directive, in registry order.
+func Directives() []Directive {
+	load()
+
+	output := make([]Directive, len(registry.ordered))
+	for idx, directive := range registry.ordered {
+		directive.Sections = append([]string(nil), directive.Sections...)
+		output[idx] = directive
+	}
+
+	return output
+}
+
+// Lookup returns the directive of the given name, as used in the given section (e.g. "Service", "ExecStart").
+func Lookup(section, name string) (Directive, bool) {
+	load()
+
+	idx, ok := registry.index[section][name]
+	if !(ok) {
+		return Directive{}, false
+	}
+
+	directive := registry.ordered[idx]
+	directive.Sections = append([]string(nil), directive.Sections...)
+
+	return directive, true
+}
@@ -0,0 +1,110 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+// directives returns the directive names of the given section structure's "systemd" struct tags, including those of embedded structures.
+func directives(structure reflect.Type) (names []string) {
+	for i := 0; i < structure.NumField(); i++ {
+		field := structure.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			names = append(names, directives(field.Type)...)
+			continue
+		}
+
+		if v, ok := field.Tag.Lookup("systemd"); ok {
+			name, options, _ := strings.Cut(v, ",")
+			if name == "-" || strings.Contains(options, "prefix") {
+				continue
+			}
+
+			names = append(names, name)
+		}
+	}
+
+	return
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("Lookup-Test", func(t *testing.T) {
+		directive, ok := systemd.Lookup("Service", "ExecStart")
+		if !(ok) {
+			t.Fatalf("expected ExecStart to be registered in [Service]")
+		}
+
+		if directive.Type != systemd.TypeCommand || !(directive.Repeatable) || !(directive.Resettable) {
+			t.Errorf("unexpected ExecStart metadata: %+v", directive)
+		}
+
+		if directive, ok := systemd.Lookup("Unit", "After"); !(ok) || !(directive.Repeatable) || directive.Resettable {
+			t.Errorf("expected After= to be repeatable, but not resettable: %+v", directive)
+		}
+
+		directive, ok = systemd.Lookup("Service", "MemoryLimit")
+		if !(ok) || directive.Deprecated == 0 || directive.Replacement != "MemoryMax" {
+			t.Errorf("unexpected MemoryLimit metadata: %+v", directive)
+		}
+
+		if directive, ok := systemd.Lookup("Service", "ProtectProc"); !(ok) || directive.Since != 247 {
+			t.Errorf("unexpected ProtectProc metadata: %+v", directive)
+		}
+
+		if directive, ok := systemd.Lookup("Service", "TimerSlackNSec"); !(ok) || directive.Type != systemd.TypeTimespan || directive.DefaultUnit != "ns" {
+			t.Errorf("unexpected TimerSlackNSec metadata: %+v", directive)
+		}
+
+		if _, ok := systemd.Lookup("Unit", "ExecStart"); ok {
+			t.Errorf("expected ExecStart to be absent from [Unit]")
+		}
+
+		if directive, ok := systemd.Lookup("Unit", "ConditionPathExists"); !(ok) || directive.Type != systemd.TypeCondition {
+			t.Errorf("unexpected ConditionPathExists metadata: %+v", directive)
+		}
+	})
+
+	t.Run("Coverage-Test", func(t *testing.T) {
+		sections := map[string]reflect.Type{
+			"Unit":      reflect.TypeOf(systemd.Unit{}),
+			"Service":   reflect.TypeOf(systemd.Service{}),
+			"Install":   reflect.TypeOf(systemd.Install{}),
+			"Socket":    reflect.TypeOf(systemd.Socket{}),
+			"Timer":     reflect.TypeOf(systemd.Timer{}),
+			"Path":      reflect.TypeOf(systemd.Path{}),
+			"Mount":     reflect.TypeOf(systemd.Mount{}),
+			"Automount": reflect.TypeOf(systemd.Automount{}),
+			"Swap":      reflect.TypeOf(systemd.Swap{}),
+			"Slice":     reflect.TypeOf(systemd.Slice{}),
+			"Scope":     reflect.TypeOf(systemd.Scope{}),
+		}
+
+		for section, structure := range sections {
+			for _, name := range directives(structure) {
+				if _, ok := systemd.Lookup(section, name); !(ok) {
+					t.Errorf("directive %s= of [%s] is missing from the registry", name, section)
+				}
+			}
+		}
+	})
+
+	t.Run("List-Accumulation-Test", func(t *testing.T) {
+		content := []byte("[Unit]\nAfter=network.target\nAfter=syslog.target\nDescription=First\nDescription=Second\n")
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Unit.After != "network.target syslog.target" {
+			t.Errorf("unexpected after: %q", instance.Unit.After)
+		}
+
+		if instance.Unit.Description != "Second" {
+			t.Errorf("unexpected description: %q", instance.Unit.Description)
+		}
+	})
+}
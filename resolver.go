@@ -0,0 +1,323 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrMasked is returned when loading a masked unit; i.e. a unit whose winning fragment is a symbolic link to /dev/null, or an empty file.
+var ErrMasked = errors.New("unit is masked")
+
+// ReadLinkFS is implemented by file systems supporting symbolic links, such as the one returned by [DirFS]. The [Resolver] requires symbolic link
+// support to detect masks and aliases; with other file systems, symbolic links are followed transparently.
+type ReadLinkFS interface {
+	fs.FS
+
+	// ReadLink returns the destination of the named symbolic link.
+	ReadLink(name string) (string, error)
+
+	// Lstat returns a [fs.FileInfo] describing the named file, without following symbolic links.
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// directory represents a [ReadLinkFS] rooted at a directory of the host's file system.
+type directory struct {
+	fs.FS
+
+	root string
+}
+
+// DirFS returns a file system for the tree of files rooted at the given directory (e.g. "/" for the running system, or the root of a mounted image).
+// Absolute symbolic link destinations are interpreted relative to the root, as with chroot(2).
+func DirFS(root string) ReadLinkFS {
+	return directory{FS: os.DirFS(root), root: root}
+}
+
+func (d directory) ReadLink(name string) (string, error) {
+	if !(fs.ValidPath(name)) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.Readlink(filepath.Join(d.root, filepath.FromSlash(name)))
+}
+
+func (d directory) Lstat(name string) (fs.FileInfo, error) {
+	if !(fs.ValidPath(name)) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.Lstat(filepath.Join(d.root, filepath.FromSlash(name)))
+}
+
+// SystemPaths returns the unit search path of the system manager, from the highest to the lowest priority; see systemd-analyze unit-paths.
+//
+// See [systemd.unit] for additional details.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Unit%20File%20Load%20Path
+func SystemPaths() []string {
+	return []string{
+		"/etc/systemd/system.control",
+		"/run/systemd/system.control",
+		"/run/systemd/transient",
+		"/run/systemd/generator.early",
+		"/etc/systemd/system",
+		"/etc/systemd/system.attached",
+		"/run/systemd/system",
+		"/run/systemd/system.attached",
+		"/run/systemd/generator",
+		"/usr/local/lib/systemd/system",
+		"/usr/lib/systemd/system",
+		"/run/systemd/generator.late",
+	}
+}
+
+// UserPaths returns the unit search path of a user manager, from the highest to the lowest priority, derived from the XDG base directory variables
+// of the given environment (e.g. [os.Getenv]). Unset variables fall back to their XDG defaults; $HOME and $XDG_RUNTIME_DIR are expected to be set.
+//
+// See [systemd.unit] for additional details.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Unit%20File%20Load%20Path
+func UserPaths(getenv func(string) string) []string {
+	lookup := func(key, fallback string) string {
+		if v := getenv(key); v != "" {
+			return v
+		}
+
+		return fallback
+	}
+
+	home := getenv("HOME")
+	runtime := getenv("XDG_RUNTIME_DIR")
+	configuration := lookup("XDG_CONFIG_HOME", path.Join(home, ".config"))
+	data := lookup("XDG_DATA_HOME", path.Join(home, ".local", "share"))
+
+	paths := []string{
+		path.Join(configuration, "systemd", "user.control"),
+		path.Join(runtime, "systemd", "user.control"),
+		path.Join(runtime, "systemd", "transient"),
+		path.Join(runtime, "systemd", "generator.early"),
+		path.Join(configuration, "systemd", "user"),
+	}
+
+	for _, v := range strings.Split(lookup("XDG_CONFIG_DIRS", "/etc/xdg"), ":") {
+		if v != "" {
+			paths = append(paths, path.Join(v, "systemd", "user"))
+		}
+	}
+
+	paths = append(paths,
+		"/etc/systemd/user",
+		path.Join(runtime, "systemd", "user"),
+		"/run/systemd/user",
+		path.Join(runtime, "systemd", "generator"),
+		path.Join(data, "systemd", "user"),
+	)
+
+	for _, v := range strings.Split(lookup("XDG_DATA_DIRS", "/usr/local/share:/usr/share"), ":") {
+		if v != "" {
+			paths = append(paths, path.Join(v, "systemd", "user"))
+		}
+	}
+
+	return append(paths,
+		"/usr/local/lib/systemd/user",
+		"/usr/lib/systemd/user",
+		path.Join(runtime, "systemd", "generator.late"),
+	)
+}
+
+// Resolver implements systemd's unit file lookup over a unit search path.
+//
+// Example:
+//
+//	resolver := Resolver{FS: DirFS("/"), Paths: SystemPaths()}
+//	resolution, e := resolver.Resolve("sshd.service")
+type Resolver struct {
+	FS    fs.FS    // Specifies the file system, rooted at "/"; see [DirFS]. File systems implementing [ReadLinkFS] enable mask and alias detection.
+	Paths []string // Specifies the absolute unit search path, from the highest to the lowest priority; see [SystemPaths] and [UserPaths].
+}
+
+// Resolution represents the outcome of resolving a unit name against a unit search path.
+type Resolution struct {
+	Name     string   `json:"Name" yaml:"Name"`                             // Specifies the requested unit name, e.g. "getty@tty1.service".
+	Unit     string   `json:"Unit" yaml:"Unit"`                             // Specifies the unit name the fragment was found as; differs from Name for aliases and template instances.
+	Fragment string   `json:"Fragment,omitempty" yaml:"Fragment,omitempty"` // Specifies the path of the winning unit file, with symbolic links resolved; empty if masked.
+	Masked   bool     `json:"Masked,omitempty" yaml:"Masked,omitempty"`     // Reports whether the unit is masked.
+	Mask     string   `json:"Mask,omitempty" yaml:"Mask,omitempty"`         // Specifies the path of the mask (e.g. a symbolic link to /dev/null), if masked.
+	Aliases  []string `json:"Aliases,omitempty" yaml:"Aliases,omitempty"`   // Specifies the paths of the alias symbolic links followed, in order.
+	Shadowed []string `json:"Shadowed,omitempty" yaml:"Shadowed,omitempty"` // Specifies the paths of lower-priority files of the unit's names - the requested name and each alias - overridden by the winning files.
+}
+
+// relative returns the file system path of an absolute path.
+func relative(p string) string {
+	if v := strings.TrimPrefix(path.Clean(p), "/"); v != "" {
+		return v
+	}
+
+	return "."
+}
+
+// lstat returns the named file's information, without following symbolic links if the file system supports them.
+func (r Resolver) lstat(p string) (fs.FileInfo, error) {
+	if links, ok := r.FS.(ReadLinkFS); ok {
+		return links.Lstat(relative(p))
+	}
+
+	return fs.Stat(r.FS, relative(p))
+}
+
+// find returns the paths of the given unit name below the search path, in priority order.
+func (r Resolver) find(name string) []string {
+	found := make([]string, 0)
+	for _, directory := range r.Paths {
+		candidate := path.Join(directory, name)
+		if _, e := r.lstat(candidate); e == nil {
+			found = append(found, candidate)
+		}
+	}
+
+	return found
+}
+
+// follow resolves the chain of symbolic links of the given path, returning the final destination, and whether the destination masks the unit
+// (/dev/null, an empty file, or a device node).
+func (r Resolver) follow(p string) (string, bool, error) {
+	links, supported := r.FS.(ReadLinkFS)
+
+	for hops := 0; hops < 32; hops++ {
+		if p == "/dev/null" {
+			return p, true, nil
+		}
+
+		info, e := r.lstat(p)
+		if e != nil {
+			return "", false, e
+		}
+
+		if !(supported) || info.Mode()&fs.ModeSymlink == 0 {
+			masked := info.Mode()&fs.ModeDevice != 0 || (info.Mode().IsRegular() && info.Size() == 0)
+
+			return p, masked, nil
+		}
+
+		destination, e := links.ReadLink(relative(p))
+		if e != nil {
+			return "", false, e
+		}
+
+		if !(path.IsAbs(destination)) {
+			destination = path.Join(path.Dir(p), destination)
+		}
+
+		p = path.Clean(destination)
+	}
+
+	return "", false, fmt.Errorf("too many levels of symbolic links: %s", p)
+}
+
+// searched reports whether the given directory is part of the search path.
+func (r Resolver) searched(directory string) bool {
+	for _, p := range r.Paths {
+		if path.Clean(p) == directory {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve looks up the unit file of the given unit name:
+//
+//   - The unit file in the highest-priority directory of the search path wins; copies in lower-priority directories are reported as shadowed.
+//   - A unit file that is a symbolic link to /dev/null, or an empty file, masks the unit.
+//   - A symbolic link to a file of a different name within the search path is an alias, and resolved by the destination's name. Symbolic links to
+//     files outside of the search path (e.g. from systemctl link) are followed to their destination.
+//   - Instances of template units (e.g. "getty@tty1.service") without a unit file of their own resolve to the template's unit file.
+//
+// An error wrapping [fs.ErrNotExist] is returned if the unit can't be found.
+func (r Resolver) Resolve(name string) (Resolution, error) {
+	requested, e := parseName(name)
+	if e != nil {
+		return Resolution{}, e
+	}
+
+	resolution := Resolution{Name: name, Unit: name}
+
+	for hops := 0; hops < 32; hops++ {
+		found := r.find(resolution.Unit)
+		if v, _ := parseName(resolution.Unit); len(found) == 0 && v.instance != "" {
+			template := v.prefix + "@" + v.kind.Suffix()
+			if found = r.find(template); len(found) > 0 {
+				resolution.Unit = template
+			}
+		}
+
+		if len(found) == 0 {
+			return resolution, fmt.Errorf("unit %s not found: %w", name, fs.ErrNotExist)
+		}
+
+		winner := found[0]
+		resolution.Shadowed = append(resolution.Shadowed, found[1:]...)
+
+		destination, masked, e := r.follow(winner)
+		if e != nil {
+			return resolution, fmt.Errorf("unable to resolve %s: %w", winner, e)
+		}
+
+		if masked {
+			resolution.Masked, resolution.Mask = true, winner
+
+			return resolution, nil
+		}
+
+		alias := path.Base(destination)
+		if alias == resolution.Unit || !(r.searched(path.Dir(destination))) {
+			resolution.Fragment = destination
+
+			return resolution, nil
+		}
+
+		if v, e := parseName(alias); e != nil || v.kind != requested.kind {
+			return resolution, fmt.Errorf("invalid alias %s of %s: %q", winner, name, alias)
+		}
+
+		resolution.Aliases = append(resolution.Aliases, winner)
+		resolution.Unit = alias
+	}
+
+	return resolution, fmt.Errorf("too many levels of aliases: %s", name)
+}
+
+// Load resolves the unit of the given name (see [Resolver.Resolve]), and returns the effective Daemon, with the drop-ins of each of the unit's names
+// applied from every directory of the search path (see [DropIns]) - i.e. for an alias, the drop-ins of both the alias and the unit it resolves to.
+// Symbolic links of drop-ins are resolved through the Resolver's file system, as fragments are. [ErrMasked] is returned for masked units. See
+// [LoadEffective] for additional details.
+func (r Resolver) Load(name string, settings ...Option) (*Daemon, error) {
+	resolution, e := r.Resolve(name)
+	if e != nil {
+		return nil, e
+	}
+
+	if resolution.Masked {
+		return nil, fmt.Errorf("unable to load %s: %w", name, ErrMasked)
+	}
+
+	directories := make([]string, 0, len(r.Paths))
+	for _, p := range r.Paths {
+		directories = append(directories, relative(p))
+	}
+
+	names := []string{name}
+	for _, alias := range resolution.Aliases {
+		names = append(names, path.Base(alias))
+	}
+
+	names = append(names, resolution.Unit)
+
+	return effective(r.FS, relative(resolution.Fragment), directories, names, settings...)
+}
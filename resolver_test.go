@@ -0,0 +1,233 @@
+package systemd_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestResolver(t *testing.T) {
+	root := t.TempDir()
+
+	write := func(name, content string) {
+		p := filepath.Join(root, name)
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.WriteFile(p, []byte(content), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	link := func(destination, name string) {
+		p := filepath.Join(root, name)
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.Symlink(destination, p); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	write("usr/lib/systemd/system/example.service", "[Service]\nExecStart=/usr/bin/vendor\n")
+	write("etc/systemd/system/example.service", "[Service]\nExecStart=/usr/bin/local\n")
+	write("etc/systemd/system/example.service.d/20-memory.conf", "[Service]\nMemoryMax=1G\n")
+	write("usr/lib/systemd/system/service.d/10-vendor.conf", "[Service]\nMemoryMax=2G\nUser=vendor\n")
+	write("usr/lib/systemd/system/getty@.service", "[Service]\nExecStart=/sbin/agetty %I\n")
+	write("usr/lib/systemd/system/vendor.service", "[Service]\nExecStart=/usr/bin/vendor\n")
+	write("usr/lib/systemd/system/empty.service", "")
+	write("opt/application/linked.service", "[Service]\nExecStart=/opt/application/bin/linked\n")
+	write("opt/application/hardening.conf", "[Service]\nNoNewPrivileges=yes\n")
+	write("etc/systemd/system/alias.service.d/30-restart.conf", "[Service]\nRestart=always\n")
+	write("usr/lib/systemd/system/nickname.service", "[Service]\nExecStart=/usr/bin/nickname\n")
+
+	link("/dev/null", "etc/systemd/system/vendor.service")
+	link("/usr/lib/systemd/system/example.service", "usr/lib/systemd/system/alias.service")
+	link("/usr/lib/systemd/system/example.service", "etc/systemd/system/nickname.service")
+	link("/opt/application/linked.service", "etc/systemd/system/linked.service")
+	link("loop-b.service", "usr/lib/systemd/system/loop-a.service")
+	link("loop-a.service", "usr/lib/systemd/system/loop-b.service")
+	link("/opt/application/hardening.conf", "etc/systemd/system/linked.service.d/30-hardening.conf")
+
+	resolver := systemd.Resolver{FS: systemd.DirFS(root), Paths: systemd.SystemPaths()}
+
+	t.Run("Resolver-Precedence-Test", func(t *testing.T) {
+		resolution, e := resolver.Resolve("example.service")
+		if e != nil {
+			t.Fatalf("failed resolving unit: %v", e)
+		}
+
+		if resolution.Fragment != "/etc/systemd/system/example.service" {
+			t.Errorf("unexpected fragment: %q", resolution.Fragment)
+		}
+
+		if !(reflect.DeepEqual(resolution.Shadowed, []string{"/usr/lib/systemd/system/example.service"})) {
+			t.Errorf("unexpected shadowed copies: %v", resolution.Shadowed)
+		}
+	})
+
+	t.Run("Resolver-Mask-Test", func(t *testing.T) {
+		for _, name := range []string{"vendor.service", "empty.service"} {
+			resolution, e := resolver.Resolve(name)
+			if e != nil {
+				t.Fatalf("failed resolving unit: %v", e)
+			}
+
+			if !(resolution.Masked) || resolution.Fragment != "" {
+				t.Errorf("expected %s to be masked: %+v", name, resolution)
+			}
+
+			if _, e := resolver.Load(name); !(errors.Is(e, systemd.ErrMasked)) {
+				t.Errorf("expected loading %s to fail with ErrMasked: %v", name, e)
+			}
+		}
+	})
+
+	t.Run("Resolver-Alias-Test", func(t *testing.T) {
+		resolution, e := resolver.Resolve("alias.service")
+		if e != nil {
+			t.Fatalf("failed resolving unit: %v", e)
+		}
+
+		if resolution.Unit != "example.service" || resolution.Fragment != "/etc/systemd/system/example.service" {
+			t.Errorf("expected the alias to resolve to the winning example.service: %+v", resolution)
+		}
+
+		if !(reflect.DeepEqual(resolution.Aliases, []string{"/usr/lib/systemd/system/alias.service"})) {
+			t.Errorf("unexpected aliases: %v", resolution.Aliases)
+		}
+
+		resolution, e = resolver.Resolve("nickname.service")
+		if e != nil {
+			t.Fatalf("failed resolving unit: %v", e)
+		}
+
+		if resolution.Unit != "example.service" || resolution.Fragment != "/etc/systemd/system/example.service" {
+			t.Errorf("expected the shadowing alias to resolve to the winning example.service: %+v", resolution)
+		}
+
+		expectation := []string{"/usr/lib/systemd/system/nickname.service", "/usr/lib/systemd/system/example.service"}
+		if !(reflect.DeepEqual(resolution.Shadowed, expectation)) {
+			t.Errorf("unexpected shadowed copies: %v", resolution.Shadowed)
+		}
+
+		if _, e := resolver.Resolve("loop-a.service"); e == nil {
+			t.Errorf("expected an error for an alias loop")
+		}
+	})
+
+	t.Run("Resolver-Linked-Test", func(t *testing.T) {
+		resolution, e := resolver.Resolve("linked.service")
+		if e != nil {
+			t.Fatalf("failed resolving unit: %v", e)
+		}
+
+		if resolution.Fragment != "/opt/application/linked.service" || len(resolution.Aliases) > 0 {
+			t.Errorf("unexpected linked unit resolution: %+v", resolution)
+		}
+	})
+
+	t.Run("Resolver-Template-Test", func(t *testing.T) {
+		resolution, e := resolver.Resolve("getty@tty1.service")
+		if e != nil {
+			t.Fatalf("failed resolving unit: %v", e)
+		}
+
+		if resolution.Unit != "getty@.service" || resolution.Fragment != "/usr/lib/systemd/system/getty@.service" {
+			t.Errorf("unexpected template resolution: %+v", resolution)
+		}
+
+		if _, e := resolver.Resolve("missing.service"); !(errors.Is(e, fs.ErrNotExist)) {
+			t.Errorf("expected fs.ErrNotExist for a missing unit: %v", e)
+		}
+	})
+
+	t.Run("Resolver-Load-Test", func(t *testing.T) {
+		instance, e := resolver.Load("example.service")
+		if e != nil {
+			t.Fatalf("failed loading unit: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStart, []string{"/usr/bin/local"})) {
+			t.Errorf("unexpected exec-start: %q", instance.Service.ExecStart)
+		}
+
+		if instance.Service.MemoryMax != "1G" || instance.Service.User != "vendor" {
+			t.Errorf("unexpected drop-in application: %q, %q", instance.Service.MemoryMax, instance.Service.User)
+		}
+
+		expectation := []string{"/usr/lib/systemd/system/service.d/10-vendor.conf", "/etc/systemd/system/example.service.d/20-memory.conf"}
+		if !(reflect.DeepEqual(instance.DropIns, expectation)) {
+			t.Errorf("unexpected drop-ins: %v", instance.DropIns)
+		}
+
+		if instance.Source != "/etc/systemd/system/example.service" {
+			t.Errorf("unexpected source: %q", instance.Source)
+		}
+	})
+
+	t.Run("Resolver-Load-Alias-Test", func(t *testing.T) {
+		instance, e := resolver.Load("alias.service")
+		if e != nil {
+			t.Fatalf("failed loading unit: %v", e)
+		}
+
+		if instance.Service.Restart != "always" || instance.Service.MemoryMax != "1G" {
+			t.Errorf("expected the drop-ins of both the alias and example.service to apply: %q, %q", instance.Service.Restart, instance.Service.MemoryMax)
+		}
+
+		expectation := []string{
+			"/usr/lib/systemd/system/service.d/10-vendor.conf",
+			"/etc/systemd/system/example.service.d/20-memory.conf",
+			"/etc/systemd/system/alias.service.d/30-restart.conf",
+		}
+
+		if !(reflect.DeepEqual(instance.DropIns, expectation)) {
+			t.Errorf("unexpected drop-ins: %v", instance.DropIns)
+		}
+	})
+
+	t.Run("Resolver-Load-Linked-Drop-In-Test", func(t *testing.T) {
+		// The drop-in's absolute symbolic link destination is resolved below the Resolver's root, rather than the host's.
+		instance, e := resolver.Load("linked.service")
+		if e != nil {
+			t.Fatalf("failed loading unit: %v", e)
+		}
+
+		if instance.Service.NoNewPrivileges != "yes" {
+			t.Errorf("expected the linked drop-in to apply: %q", instance.Service.NoNewPrivileges)
+		}
+
+		if !(reflect.DeepEqual(instance.DropIns, []string{"/usr/lib/systemd/system/service.d/10-vendor.conf", "/opt/application/hardening.conf"})) {
+			t.Errorf("unexpected drop-ins: %v", instance.DropIns)
+		}
+	})
+
+	t.Run("User-Paths-Test", func(t *testing.T) {
+		environment := map[string]string{"HOME": "/home/user", "XDG_RUNTIME_DIR": "/run/user/1000"}
+
+		paths := systemd.UserPaths(func(key string) string { return environment[key] })
+		if paths[0] != "/home/user/.config/systemd/user.control" || paths[4] != "/home/user/.config/systemd/user" {
+			t.Errorf("unexpected user search path: %v", paths)
+		}
+
+		expectation := []string{"/etc/xdg/systemd/user", "/home/user/.local/share/systemd/user", "/usr/share/systemd/user", "/run/user/1000/systemd/generator.late"}
+		for _, p := range expectation {
+			found := false
+			for _, candidate := range paths {
+				found = found || candidate == p
+			}
+
+			if !(found) {
+				t.Errorf("expected %q in the user search path: %v", p, paths)
+			}
+		}
+	})
+}
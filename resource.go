@@ -0,0 +1,30 @@
+package systemd
+
+// ResourceControl represents the cgroup resource control directives of systemd.resource-control(5), shared by the unit types that manage
+// processes: [Slice], [Scope], [Service], [Socket], [Mount] and [Swap]. Embed ResourceControl into a section type to inherit its directives;
+// they're read from the same section.
+//
+//   - Limits apply to the unit's entire cgroup subtree. Memory protections (MemoryMin=, MemoryLow=) are bounded by the protection of the unit's
+//     parent slices, while limits (MemoryHigh=, MemoryMax=, TasksMax=) of a parent slice cap every unit below it; see [SliceParents].
+//
+// See [systemd.resource-control] for additional details.
+//
+// [systemd.resource-control]: https://www.freedesktop.org/software/systemd/man/latest/systemd.resource-control.html
+type ResourceControl struct {
+	CPUAccounting    string `json:"CPUAccounting,omitempty" yaml:"CPUAccounting,omitempty" ini:"CPUAccounting,omitempty" systemd:"CPUAccounting,omitempty"`             // If true, enables CPU usage accounting for the unit.
+	CPUWeight        string `json:"CPUWeight,omitempty" yaml:"CPUWeight,omitempty" ini:"CPUWeight,omitempty" systemd:"CPUWeight,omitempty"`                             // Sets the relative CPU time weight of the unit's processes, between 1 and 10000. Defaults to 100.
+	StartupCPUWeight string `json:"StartupCPUWeight,omitempty" yaml:"StartupCPUWeight,omitempty" ini:"StartupCPUWeight,omitempty" systemd:"StartupCPUWeight,omitempty"` // Similar to CPUWeight, but only applies during system startup and shutdown.
+	CPUQuota         string `json:"CPUQuota,omitempty" yaml:"CPUQuota,omitempty" ini:"CPUQuota,omitempty" systemd:"CPUQuota,omitempty"`                                 // Sets the CPU time quota of the unit's processes, as a percentage relative to one CPU (e.g. "20%", or "200%" for two CPUs).
+	AllowedCPUs      string `json:"AllowedCPUs,omitempty" yaml:"AllowedCPUs,omitempty" ini:"AllowedCPUs,omitempty" systemd:"AllowedCPUs,omitempty"`                     // Restricts the unit's processes to the given CPU indices or ranges, e.g. "0-3 8".
+	MemoryAccounting string `json:"MemoryAccounting,omitempty" yaml:"MemoryAccounting,omitempty" ini:"MemoryAccounting,omitempty" systemd:"MemoryAccounting,omitempty"` // If true, enables memory usage accounting for the unit.
+	MemoryMin        string `json:"MemoryMin,omitempty" yaml:"MemoryMin,omitempty" ini:"MemoryMin,omitempty" systemd:"MemoryMin,omitempty"`                             // Specifies the memory usage protected from reclaim, as long as it's within the protection of the unit's parent slices.
+	MemoryLow        string `json:"MemoryLow,omitempty" yaml:"MemoryLow,omitempty" ini:"MemoryLow,omitempty" systemd:"MemoryLow,omitempty"`                             // Specifies the best-effort memory usage protection; memory below the threshold is only reclaimed if unprotected memory can't be reclaimed.
+	MemoryHigh       string `json:"MemoryHigh,omitempty" yaml:"MemoryHigh,omitempty" ini:"MemoryHigh,omitempty" systemd:"MemoryHigh,omitempty"`                         // Specifies the memory usage throttling limit; usage above the limit is throttled and aggressively reclaimed.
+	MemoryMax        string `json:"MemoryMax,omitempty" yaml:"MemoryMax,omitempty" ini:"MemoryMax,omitempty" systemd:"MemoryMax,omitempty"`                             // Specifies the absolute limit on memory usage of the unit's processes (cgroup v2), e.g. "1G".
+	MemoryLimit      string `json:"MemoryLimit,omitempty" yaml:"MemoryLimit,omitempty" ini:"MemoryLimit,omitempty" systemd:"MemoryLimit,omitempty"`                     // Specifies the absolute limit on memory usage of the unit's processes (cgroup v1). Deprecated in favor of MemoryMax.
+	MemorySwapMax    string `json:"MemorySwapMax,omitempty" yaml:"MemorySwapMax,omitempty" ini:"MemorySwapMax,omitempty" systemd:"MemorySwapMax,omitempty"`             // Specifies the absolute limit on swap usage of the unit's processes.
+	TasksAccounting  string `json:"TasksAccounting,omitempty" yaml:"TasksAccounting,omitempty" ini:"TasksAccounting,omitempty" systemd:"TasksAccounting,omitempty"`     // If true, enables task accounting for the unit.
+	TasksMax         string `json:"TasksMax,omitempty" yaml:"TasksMax,omitempty" ini:"TasksMax,omitempty" systemd:"TasksMax,omitempty"`                                 // Specifies the maximum number of tasks (processes and threads) the unit may create, as an absolute number or percentage.
+	IOAccounting     string `json:"IOAccounting,omitempty" yaml:"IOAccounting,omitempty" ini:"IOAccounting,omitempty" systemd:"IOAccounting,omitempty"`                 // If true, enables block I/O accounting for the unit.
+	IOWeight         string `json:"IOWeight,omitempty" yaml:"IOWeight,omitempty" ini:"IOWeight,omitempty" systemd:"IOWeight,omitempty"`                                 // Sets the relative block I/O weight of the unit's processes, between 1 and 10000. Defaults to 100.
+}
@@ -0,0 +1,71 @@
+package systemd
+
+import (
+	"fmt"
+)
+
+// Scope represents the [Scope] section of a systemd scope unit.
+//
+// A scope unit (e.g. "job-42.scope") groups externally created processes, rather than processes spawned by systemd itself. Scopes can't be loaded
+// from unit files: they're created at runtime through systemd-run --scope or the D-Bus StartTransientUnit() method, with their configuration
+// passed as a set of properties. See [Transient] for deriving those properties from a [Daemon].
+//
+// See [systemd.scope] for additional details.
+//
+// [systemd.scope]: https://www.freedesktop.org/software/systemd/man/latest/systemd.scope.html
+type Scope struct {
+	RuntimeMaxSec             string `json:"RuntimeMaxSec,omitempty" yaml:"RuntimeMaxSec,omitempty" ini:"RuntimeMaxSec,omitempty" systemd:"RuntimeMaxSec,omitempty"`                                                 // Configures a maximum time for the scope to run; once exceeded, the scope is terminated and put into a failure state.
+	RuntimeRandomizedExtraSec string `json:"RuntimeRandomizedExtraSec,omitempty" yaml:"RuntimeRandomizedExtraSec,omitempty" ini:"RuntimeRandomizedExtraSec,omitempty" systemd:"RuntimeRandomizedExtraSec,omitempty"` // Extends RuntimeMaxSec by a randomly selected, evenly distributed amount of time between 0 and the specified time value.
+	OOMPolicy                 string `json:"OOMPolicy,omitempty" yaml:"OOMPolicy,omitempty" ini:"OOMPolicy,omitempty" systemd:"OOMPolicy,omitempty"`                                                                 // Configures the action taken when the kernel's OOM killer terminates one of the scope's processes. One of `continue`, `stop`, `kill`.
+
+	Kill            `yaml:",inline" ini:",extends"` // Embeds the process-killing directives of systemd.kill(5). See [Kill] for additional details.
+	ResourceControl `yaml:",inline" ini:",extends"` // Embeds the resource control directives of systemd.resource-control(5). See [ResourceControl] for additional details.
+}
+
+// Property represents a single property of a transient unit, as passed to systemd-run's --property switch (e.g. "MemoryMax=1G"), or to the D-Bus
+// StartTransientUnit() method.
+type Property struct {
+	Section string `json:"Section" yaml:"Section"` // Specifies the section the property's directive belongs to, e.g. "Scope".
+	Name    string `json:"Name" yaml:"Name"`       // Specifies the property's name, e.g. "MemoryMax".
+	Value   string `json:"Value" yaml:"Value"`     // Specifies the property's value, in unit file syntax.
+}
+
+// String returns the Property in systemd-run's "Name=Value" form.
+func (p Property) String() string {
+	return p.Name + "=" + p.Value
+}
+
+// Transient returns the Daemon as the ordered set of properties of a transient unit, e.g. for a scope started with systemd-run --scope. The
+// directives of the [Unit] section and the Daemon's type-specific section are included, following the same rules as [Marshal]:
+//
+//   - Repeatable directives produce one Property per value.
+//   - When a target is configured (see [TargetVersion] and [LegacyHierarchy]), directives are rewritten, and any remaining incompatibilities are
+//     reported through a [CompatibilityError] alongside the properties.
+//
+// Transient units can't be enabled; an error is returned if the [Install] section isn't empty.
+func Transient(systemd Daemon, settings ...Option) ([]Property, error) {
+	file, e := systemd.document()
+	if e != nil {
+		return nil, e
+	}
+
+	if section := file.section("Install"); section != nil && len(section.Entries) > 0 {
+		return nil, fmt.Errorf("transient units don't support the [Install] section")
+	}
+
+	o := configure(settings...)
+	_, unresolved := downgrade(file, o)
+
+	properties := make([]Property, 0)
+	for _, section := range file.Groups {
+		for _, assignment := range section.Entries {
+			properties = append(properties, Property{Section: section.Name, Name: assignment.Key, Value: assignment.Value})
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return properties, &CompatibilityError{Target: o.target, Incompatibilities: unresolved}
+	}
+
+	return properties, nil
+}
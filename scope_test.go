@@ -0,0 +1,99 @@
+package systemd_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestTransient(t *testing.T) {
+	t.Run("Scope-Transient-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Description: "Job 42",
+			},
+			Scope: &systemd.Scope{
+				RuntimeMaxSec:   "1h",
+				OOMPolicy:       "kill",
+				Kill:            systemd.Kill{KillMode: systemd.KillModeMixed},
+				ResourceControl: systemd.ResourceControl{MemoryMax: "2G"},
+			},
+		}
+
+		properties, e := systemd.Transient(daemon)
+		if e != nil {
+			t.Fatalf("failed deriving transient properties: %v", e)
+		}
+
+		expectation := []systemd.Property{
+			{Section: "Unit", Name: "Description", Value: "Job 42"},
+			{Section: "Scope", Name: "RuntimeMaxSec", Value: "1h"},
+			{Section: "Scope", Name: "OOMPolicy", Value: "kill"},
+			{Section: "Scope", Name: "KillMode", Value: "mixed"},
+			{Section: "Scope", Name: "MemoryMax", Value: "2G"},
+		}
+
+		if !(reflect.DeepEqual(properties, expectation)) {
+			t.Errorf("unexpected transient properties: %v", properties)
+		}
+
+		if v := properties[4].String(); v != "MemoryMax=2G" {
+			t.Errorf("unexpected property string: %q", v)
+		}
+	})
+
+	t.Run("Service-Transient-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Service: systemd.Service{
+				ExecStart: []string{"/usr/bin/job"},
+				ResourceControl: systemd.ResourceControl{
+					MemoryMax: "1G",
+				},
+			},
+		}
+
+		properties, e := systemd.Transient(daemon, systemd.TargetVersion(239), systemd.LegacyHierarchy())
+		if e != nil {
+			t.Fatalf("failed deriving transient properties: %v", e)
+		}
+
+		expectation := []systemd.Property{
+			{Section: "Service", Name: "ExecStart", Value: "/usr/bin/job"},
+			{Section: "Service", Name: "MemoryLimit", Value: "1G"},
+		}
+
+		if !(reflect.DeepEqual(properties, expectation)) {
+			t.Errorf("unexpected transient properties: %v", properties)
+		}
+	})
+
+	t.Run("Scope-Compatibility-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Scope: &systemd.Scope{OOMPolicy: "stop"},
+		}
+
+		properties, e := systemd.Transient(daemon, systemd.TargetVersion(239))
+
+		var compatibility *systemd.CompatibilityError
+		if !(errors.As(e, &compatibility)) {
+			t.Fatalf("expected a compatibility error, received: %v", e)
+		}
+
+		if len(properties) != 1 {
+			t.Errorf("expected the properties alongside the compatibility error: %v", properties)
+		}
+	})
+
+	t.Run("Install-Transient-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Scope:   &systemd.Scope{},
+			Install: systemd.Install{WantedBy: "multi-user.target"},
+		}
+
+		if _, e := systemd.Transient(daemon); e == nil {
+			t.Errorf("expected an error for a transient unit with an [Install] section")
+		}
+	})
+}
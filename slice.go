@@ -0,0 +1,52 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Slice represents the [Slice] section of a systemd slice file.
+//
+// A slice unit (e.g. "tenant-a.slice") groups the processes of other units (services, scopes and further slices) into a node of the cgroup tree,
+// applying resource control to the node as a whole. Slices are arranged in a hierarchy encoded by their name: each dash separates a parent,
+// e.g. "tenant-a-batch.slice" is placed in "tenant-a.slice", which is placed in "tenant.slice", itself placed in the root slice "-.slice". See
+// [SliceParents] for resolving a slice's parents.
+//
+// See [systemd.slice] for additional details.
+//
+// [systemd.slice]: https://www.freedesktop.org/software/systemd/man/latest/systemd.slice.html
+type Slice struct {
+	ResourceControl `yaml:",inline" ini:",extends"` // Embeds the resource control directives of systemd.resource-control(5). See [ResourceControl] for additional details.
+}
+
+// SliceParents returns the parent chain of the given slice unit name, ordered from the root slice ("-.slice") down to the slice's immediate
+// parent. The chain of the root slice itself is empty.
+//
+// Example:
+//
+//	SliceParents("tenant-a-batch.slice") // [-.slice tenant.slice tenant-a.slice]
+func SliceParents(name string) ([]string, error) {
+	const suffix = ".slice"
+
+	prefix, ok := strings.CutSuffix(name, suffix)
+	if !(ok) || prefix == "" {
+		return nil, fmt.Errorf("invalid slice unit name: %q", name)
+	}
+
+	if prefix == "-" {
+		return []string{}, nil
+	}
+
+	if strings.HasPrefix(prefix, "-") || strings.HasSuffix(prefix, "-") || strings.Contains(prefix, "--") {
+		return nil, fmt.Errorf("invalid slice unit name: %q", name)
+	}
+
+	components := strings.Split(prefix, "-")
+
+	parents := []string{"-" + suffix}
+	for idx := 1; idx < len(components); idx++ {
+		parents = append(parents, strings.Join(components[:idx], "-")+suffix)
+	}
+
+	return parents, nil
+}
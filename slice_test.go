@@ -0,0 +1,94 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestSlice(t *testing.T) {
+	t.Run("Slice-Marshal-Test", func(t *testing.T) {
+		daemon := systemd.Daemon{
+			Unit: systemd.Unit{
+				Description: "Tenant A Batch Jobs",
+			},
+			Slice: &systemd.Slice{
+				ResourceControl: systemd.ResourceControl{
+					CPUWeight:  "20",
+					MemoryHigh: "6G",
+					MemoryMax:  "8G",
+				},
+			},
+		}
+
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"Description=Tenant A Batch Jobs",
+			"",
+			"[Slice]",
+			"CPUWeight=20",
+			"MemoryHigh=6G",
+			"MemoryMax=8G",
+			"",
+			"[Install]",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Slice, daemon.Slice)) {
+			t.Errorf("unexpected slice after round-trip: %+v", instance.Slice)
+		}
+	})
+
+	t.Run("Service-Slice-Test", func(t *testing.T) {
+		instance, e := systemd.Unmarshal([]byte("[Service]\nExecStart=/usr/bin/batch\nSlice=tenant-a-batch.slice\n"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Service.Slice != "tenant-a-batch.slice" {
+			t.Errorf("unexpected service slice: %q", instance.Service.Slice)
+		}
+	})
+
+	t.Run("Slice-Parents-Test", func(t *testing.T) {
+		cases := map[string][]string{
+			"-.slice":              {},
+			"tenant.slice":         {"-.slice"},
+			"tenant-a.slice":       {"-.slice", "tenant.slice"},
+			"tenant-a-batch.slice": {"-.slice", "tenant.slice", "tenant-a.slice"},
+		}
+
+		for name, expectation := range cases {
+			parents, e := systemd.SliceParents(name)
+			if e != nil {
+				t.Errorf("unexpected error for %q: %v", name, e)
+				continue
+			}
+
+			if !(reflect.DeepEqual(parents, expectation)) {
+				t.Errorf("unexpected parents of %q: %v", name, parents)
+			}
+		}
+
+		for _, name := range []string{"tenant.service", ".slice", "-tenant.slice", "tenant-.slice", "tenant--a.slice"} {
+			if _, e := systemd.SliceParents(name); e == nil {
+				t.Errorf("expected an error for %q", name)
+			}
+		}
+	})
+}
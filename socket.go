@@ -0,0 +1,58 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// boolean parses a systemd boolean value; see [TypeBoolean].
+func boolean(v string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean: %q", v)
+}
+
+// Activates returns the name of the service unit activated by the socket unit of the given name (e.g. "example.socket"):
+//
+//   - If Accept= is true, a service instance is spawned per connection, from the template service of the socket's name (e.g. "example@.service").
+//     Service= can't be combined with Accept=yes.
+//   - Otherwise, the service specified by Service=, or the service of the socket's name (e.g. "example.service").
+func (s Socket) Activates(name string) (string, error) {
+	prefix, ok := strings.CutSuffix(name, KindSocket.Suffix())
+	if !(ok) || prefix == "" {
+		return "", fmt.Errorf("invalid socket unit name: %q", name)
+	}
+
+	accept := false
+	if s.Accept != "" {
+		v, e := boolean(s.Accept)
+		if e != nil {
+			return "", fmt.Errorf("invalid Accept= directive of %s: %w", name, e)
+		}
+
+		accept = v
+	}
+
+	if accept {
+		if s.Service != "" {
+			return "", fmt.Errorf("Service= can't be combined with Accept=yes in %s", name)
+		}
+
+		return prefix + "@" + KindService.Suffix(), nil
+	}
+
+	if s.Service != "" {
+		if kind, e := KindOf(s.Service); e != nil || kind != KindService {
+			return "", fmt.Errorf("Service= of %s doesn't specify a service unit: %q", name, s.Service)
+		}
+
+		return s.Service, nil
+	}
+
+	return prefix + KindService.Suffix(), nil
+}
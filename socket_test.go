@@ -0,0 +1,155 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestAddress(t *testing.T) {
+	cases := map[string]systemd.Address{
+		"8080":                {Family: systemd.AddressPort, Port: 8080},
+		"127.0.0.1:8080":      {Family: systemd.AddressIPv4, Host: "127.0.0.1", Port: 8080},
+		"[::1]:8080":          {Family: systemd.AddressIPv6, Host: "::1", Port: 8080},
+		"[fe80::1%eth0]:8080": {Family: systemd.AddressIPv6, Host: "fe80::1%eth0", Port: 8080},
+		"/run/example.sock":   {Family: systemd.AddressUnix, Path: "/run/example.sock"},
+		"@example":            {Family: systemd.AddressAbstract, Path: "example"},
+		"vsock:2:1234":        {Family: systemd.AddressVSock, Host: "2", Port: 1234},
+		"vsock::1234":         {Family: systemd.AddressVSock, Port: 1234},
+	}
+
+	for v, expectation := range cases {
+		address, e := systemd.ParseAddress(v)
+		if e != nil {
+			t.Errorf("unexpected error for %q: %v", v, e)
+			continue
+		}
+
+		if address != expectation {
+			t.Errorf("unexpected address of %q: %+v", v, address)
+		}
+
+		if address.String() != v {
+			t.Errorf("unexpected address string of %q: %q", v, address.String())
+		}
+	}
+
+	for _, v := range []string{"", "0", "@", "localhost:8080", "127.0.0.1", "vsock:2", "vsock:x:1234", "[::1]:0"} {
+		if _, e := systemd.ParseAddress(v); e == nil {
+			t.Errorf("expected an error for %q", v)
+		}
+	}
+}
+
+// [Unit]
+// Description=Example Socket
+//
+// [Socket]
+// ListenStream=8080
+// ListenStream=/run/example.sock
+// ListenDatagram=[::1]:8125
+// FileDescriptorName=example
+//
+// [Install]
+// WantedBy=sockets.target
+func TestSocket(t *testing.T) {
+	daemon := systemd.Daemon{
+		Kind: systemd.KindSocket,
+		Unit: systemd.Unit{
+			Description: "Example Socket",
+		},
+		Install: systemd.Install{
+			WantedBy: "sockets.target",
+		},
+		Socket: &systemd.Socket{
+			ListenStream: []systemd.Address{
+				{Family: systemd.AddressPort, Port: 8080},
+				{Family: systemd.AddressUnix, Path: "/run/example.sock"},
+			},
+			ListenDatagram:     []systemd.Address{{Family: systemd.AddressIPv6, Host: "::1", Port: 8125}},
+			FileDescriptorName: "example",
+		},
+	}
+
+	t.Run("Socket-Marshal-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon, systemd.UnitName("example.socket"))
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"Description=Example Socket",
+			"",
+			"[Socket]",
+			"ListenStream=8080",
+			"ListenStream=/run/example.sock",
+			"ListenDatagram=[::1]:8125",
+			"FileDescriptorName=example",
+			"",
+			"[Install]",
+			"WantedBy=sockets.target",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+
+		instance, e := systemd.Unmarshal(content, systemd.UnitName("example.socket"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Socket, daemon.Socket)) || instance.Kind != systemd.KindSocket {
+			t.Errorf("unexpected socket after round-trip: %+v", instance.Socket)
+		}
+	})
+
+	t.Run("Socket-Invalid-Address-Test", func(t *testing.T) {
+		if _, e := systemd.Unmarshal([]byte("[Socket]\nListenStream=localhost:8080\n")); e == nil {
+			t.Errorf("expected an error for a host name listening address")
+		}
+	})
+
+	t.Run("Socket-Activates-Test", func(t *testing.T) {
+		cases := []struct {
+			Socket      systemd.Socket
+			Expectation string
+		}{
+			{Socket: systemd.Socket{}, Expectation: "example.service"},
+			{Socket: systemd.Socket{Service: "backend.service"}, Expectation: "backend.service"},
+			{Socket: systemd.Socket{Accept: "yes"}, Expectation: "example@.service"},
+			{Socket: systemd.Socket{Accept: "no", Service: "backend.service"}, Expectation: "backend.service"},
+		}
+
+		for _, c := range cases {
+			service, e := c.Socket.Activates("example.socket")
+			if e != nil {
+				t.Errorf("unexpected error for %+v: %v", c.Socket, e)
+				continue
+			}
+
+			if service != c.Expectation {
+				t.Errorf("unexpected activated service for %+v: %q", c.Socket, service)
+			}
+		}
+
+		invalid := []systemd.Socket{
+			{Accept: "yes", Service: "backend.service"},
+			{Service: "backend.target"},
+			{Accept: "maybe"},
+		}
+
+		for _, socket := range invalid {
+			if _, e := socket.Activates("example.socket"); e == nil {
+				t.Errorf("expected an error for %+v", socket)
+			}
+		}
+
+		if _, e := (systemd.Socket{}).Activates("example.service"); e == nil {
+			t.Errorf("expected an error for a non-socket unit name")
+		}
+	})
+}
@@ -0,0 +1,417 @@
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Host resolves the host-dependent specifiers of unit files - e.g. %H (the hostname), %m (the machine ID) and %t (the runtime directory) - as
+// documented by [systemd.unit]. See [Machine] for a Host of fixed values, and [LocalMachine] for the host's own values.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Specifiers
+type Host interface {
+	// Specifier returns the value of the given host-dependent specifier, e.g. 'H'. An error is returned for specifiers the Host can't resolve.
+	Specifier(specifier byte) (string, error)
+}
+
+// Machine represents the host-dependent values of specifiers, for either the system or a user service manager. Empty values expand to empty
+// strings, as systemd does for unset os-release(5) fields.
+type Machine struct {
+	Architecture   string            `json:"Architecture,omitempty" yaml:"Architecture,omitempty"`     // Specifies the architecture (%a), e.g. "x86-64".
+	Hostname       string            `json:"Hostname,omitempty" yaml:"Hostname,omitempty"`             // Specifies the hostname (%H); the short hostname (%l) is derived from it.
+	PrettyHostname string            `json:"PrettyHostname,omitempty" yaml:"PrettyHostname,omitempty"` // Specifies the pretty hostname (%q); defaults to the hostname.
+	MachineID      string            `json:"MachineID,omitempty" yaml:"MachineID,omitempty"`           // Specifies the machine ID (%m), as read from /etc/machine-id.
+	BootID         string            `json:"BootID,omitempty" yaml:"BootID,omitempty"`                 // Specifies the boot ID (%b), without dashes.
+	KernelRelease  string            `json:"KernelRelease,omitempty" yaml:"KernelRelease,omitempty"`   // Specifies the kernel release (%v), as reported by uname -r.
+	Release        map[string]string `json:"Release,omitempty" yaml:"Release,omitempty"`               // Specifies the os-release(5) fields: ID (%o), VERSION_ID (%w), BUILD_ID (%B), VARIANT_ID (%W), IMAGE_ID (%M) and IMAGE_VERSION (%A).
+
+	User  string `json:"User,omitempty" yaml:"User,omitempty"`   // Specifies the name of the user running the service manager (%u), e.g. "root".
+	UID   string `json:"UID,omitempty" yaml:"UID,omitempty"`     // Specifies the UID of the user running the service manager (%U).
+	Group string `json:"Group,omitempty" yaml:"Group,omitempty"` // Specifies the primary group of the user running the service manager (%g).
+	GID   string `json:"GID,omitempty" yaml:"GID,omitempty"`     // Specifies the GID of the user running the service manager (%G).
+	Home  string `json:"Home,omitempty" yaml:"Home,omitempty"`   // Specifies the home directory of the user running the service manager (%h), e.g. "/root".
+	Shell string `json:"Shell,omitempty" yaml:"Shell,omitempty"` // Specifies the shell of the user running the service manager (%s), e.g. "/bin/sh".
+
+	RuntimeDirectory       string `json:"RuntimeDirectory,omitempty" yaml:"RuntimeDirectory,omitempty"`             // Specifies the runtime directory root (%t), e.g. "/run".
+	StateDirectory         string `json:"StateDirectory,omitempty" yaml:"StateDirectory,omitempty"`                 // Specifies the state directory root (%S), e.g. "/var/lib".
+	CacheDirectory         string `json:"CacheDirectory,omitempty" yaml:"CacheDirectory,omitempty"`                 // Specifies the cache directory root (%C), e.g. "/var/cache".
+	LogsDirectory          string `json:"LogsDirectory,omitempty" yaml:"LogsDirectory,omitempty"`                   // Specifies the log directory root (%L), e.g. "/var/log".
+	ConfigurationDirectory string `json:"ConfigurationDirectory,omitempty" yaml:"ConfigurationDirectory,omitempty"` // Specifies the configuration directory root (%E), e.g. "/etc".
+	DataDirectory          string `json:"DataDirectory,omitempty" yaml:"DataDirectory,omitempty"`                   // Specifies the shared data directory root (%D), e.g. "/usr/share".
+	CredentialsDirectory   string `json:"CredentialsDirectory,omitempty" yaml:"CredentialsDirectory,omitempty"`     // Specifies the credentials directory root; %d expands to its subdirectory of the unit's name, e.g. "/run/credentials/example.service".
+	TemporaryDirectory     string `json:"TemporaryDirectory,omitempty" yaml:"TemporaryDirectory,omitempty"`         // Specifies the temporary directory (%T); defaults to "/tmp".
+	PersistentDirectory    string `json:"PersistentDirectory,omitempty" yaml:"PersistentDirectory,omitempty"`       // Specifies the persistent temporary directory (%V); defaults to "/var/tmp".
+}
+
+// Specifier implements [Host].
+func (m Machine) Specifier(specifier byte) (string, error) {
+	fallback := func(v, fallback string) string {
+		if v == "" {
+			return fallback
+		}
+
+		return v
+	}
+
+	switch specifier {
+	case 'a':
+		return m.Architecture, nil
+	case 'A':
+		return m.Release["IMAGE_VERSION"], nil
+	case 'b':
+		return m.BootID, nil
+	case 'B':
+		return m.Release["BUILD_ID"], nil
+	case 'C':
+		return m.CacheDirectory, nil
+	case 'd':
+		return m.CredentialsDirectory, nil
+	case 'D':
+		return m.DataDirectory, nil
+	case 'E':
+		return m.ConfigurationDirectory, nil
+	case 'g':
+		return m.Group, nil
+	case 'G':
+		return m.GID, nil
+	case 'h':
+		return m.Home, nil
+	case 'H':
+		return m.Hostname, nil
+	case 'l':
+		v, _, _ := strings.Cut(m.Hostname, ".")
+		return v, nil
+	case 'L':
+		return m.LogsDirectory, nil
+	case 'm':
+		return m.MachineID, nil
+	case 'M':
+		return m.Release["IMAGE_ID"], nil
+	case 'o':
+		return m.Release["ID"], nil
+	case 'q':
+		return fallback(m.PrettyHostname, m.Hostname), nil
+	case 's':
+		return m.Shell, nil
+	case 'S':
+		return m.StateDirectory, nil
+	case 't':
+		return m.RuntimeDirectory, nil
+	case 'T':
+		return fallback(m.TemporaryDirectory, "/tmp"), nil
+	case 'u':
+		return m.User, nil
+	case 'U':
+		return m.UID, nil
+	case 'v':
+		return m.KernelRelease, nil
+	case 'V':
+		return fallback(m.PersistentDirectory, "/var/tmp"), nil
+	case 'w':
+		return m.Release["VERSION_ID"], nil
+	case 'W':
+		return m.Release["VARIANT_ID"], nil
+	}
+
+	return "", fmt.Errorf("unknown specifier: %%%c", specifier)
+}
+
+// architectures maps Go's architectures to systemd's architecture identifiers; see systemd-analyze architectures.
+var architectures = map[string]string{
+	"386":      "x86",
+	"amd64":    "x86-64",
+	"arm":      "arm",
+	"arm64":    "arm64",
+	"loong64":  "loongarch64",
+	"mips":     "mips",
+	"mipsle":   "mips-le",
+	"mips64":   "mips64",
+	"mips64le": "mips64-le",
+	"ppc64":    "ppc64",
+	"ppc64le":  "ppc64-le",
+	"riscv64":  "riscv64",
+	"s390x":    "s390x",
+}
+
+// release parses the key-value assignments of an os-release(5) file.
+func release(content []byte) map[string]string {
+	fields := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(text, "="); ok {
+			fields[key] = strings.Trim(value, `"'`)
+		}
+	}
+
+	return fields
+}
+
+// LocalMachine returns the host-dependent values of the running host's system service manager, or, if user is true, of the current user's
+// service manager - where directories follow the XDG base directory variables of the environment, as [UserPaths] does. Values that can't be read
+// (e.g. a missing /etc/machine-id) are left empty.
+func LocalMachine(user bool) Machine {
+	read := func(p string) string {
+		content, _ := os.ReadFile(p)
+
+		return strings.TrimSpace(string(content))
+	}
+
+	m := Machine{
+		Architecture:           architectures[runtime.GOARCH],
+		MachineID:              read("/etc/machine-id"),
+		BootID:                 strings.ReplaceAll(read("/proc/sys/kernel/random/boot_id"), "-", ""),
+		KernelRelease:          read("/proc/sys/kernel/osrelease"),
+		PrettyHostname:         release([]byte(read("/etc/machine-info")))["PRETTY_HOSTNAME"],
+		User:                   "root",
+		UID:                    "0",
+		Group:                  "root",
+		GID:                    "0",
+		Home:                   "/root",
+		Shell:                  "/bin/sh",
+		RuntimeDirectory:       "/run",
+		StateDirectory:         "/var/lib",
+		CacheDirectory:         "/var/cache",
+		LogsDirectory:          "/var/log",
+		ConfigurationDirectory: "/etc",
+		DataDirectory:          "/usr/share",
+		CredentialsDirectory:   "/run/credentials",
+	}
+
+	m.Hostname, _ = os.Hostname()
+
+	if content, e := os.ReadFile("/etc/os-release"); e == nil {
+		m.Release = release(content)
+	} else if content, e := os.ReadFile("/usr/lib/os-release"); e == nil {
+		m.Release = release(content)
+	}
+
+	if !(user) {
+		return m
+	}
+
+	m.User, m.UID, m.Group, m.GID, m.Home, m.Shell = "", "", "", "", os.Getenv("HOME"), os.Getenv("SHELL")
+	account(&m)
+
+	lookup := func(key, fallback string) string {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+
+		return fallback
+	}
+
+	m.RuntimeDirectory = os.Getenv("XDG_RUNTIME_DIR")
+	m.StateDirectory = lookup("XDG_STATE_HOME", path.Join(m.Home, ".local", "state"))
+	m.CacheDirectory = lookup("XDG_CACHE_HOME", path.Join(m.Home, ".cache"))
+	m.LogsDirectory = path.Join(m.StateDirectory, "log")
+	m.ConfigurationDirectory = lookup("XDG_CONFIG_HOME", path.Join(m.Home, ".config"))
+	m.DataDirectory = lookup("XDG_DATA_HOME", path.Join(m.Home, ".local", "share"))
+	m.CredentialsDirectory = path.Join(m.RuntimeDirectory, "credentials")
+
+	return m
+}
+
+// account sets the Machine's user, group and home directory from the current user, if known.
+func account(m *Machine) {
+	current, e := user.Current()
+	if e != nil {
+		return
+	}
+
+	m.User, m.UID, m.GID, m.Home = current.Username, current.Uid, current.Gid, current.HomeDir
+	if g, e := user.LookupGroupId(current.Gid); e == nil {
+		m.Group = g.Name
+	}
+}
+
+// specifiers returns the specifiers derived from a unit name, as documented by [systemd.unit]:
+//
+//   - %n: the full unit name, e.g. "getty@tty1.service".
+//   - %N: the full unit name, without the type suffix, e.g. "getty@tty1".
+//   - %p, %P: the (unescaped) prefix, e.g. "getty".
+//   - %i, %I: the (unescaped) instance, e.g. "tty1"; empty for units other than template instances.
+//   - %j, %J: the (unescaped) final dash-separated component of the prefix, e.g. "getty".
+//   - %f: the unescaped file system path of the instance, or the prefix for units other than template instances.
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Specifiers
+func specifiers(name string) (map[byte]string, error) {
+	v, e := parseName(name)
+	if e != nil {
+		return nil, e
+	}
+
+	final := v.prefix
+	if idx := strings.LastIndex(final, "-"); idx >= 0 {
+		final = final[idx+1:]
+	}
+
+	table := map[byte]string{
+		'n': name,
+		'N': strings.TrimSuffix(name, v.kind.Suffix()),
+		'p': v.prefix,
+		'i': v.instance,
+		'j': final,
+	}
+
+	for escaped, unescaped := range map[byte]byte{'p': 'P', 'i': 'I', 'j': 'J'} {
+		if table[unescaped], e = Unescape(table[escaped]); e != nil {
+			return nil, e
+		}
+	}
+
+	f := v.prefix
+	if v.instance != "" {
+		f = v.instance
+	}
+
+	if table['f'], e = UnescapePath(f); e != nil {
+		return nil, e
+	}
+
+	return table, nil
+}
+
+// expand replaces each specifier of the value - including "%%" - with the result of the given function. A "%" at the end of the value is kept
+// as-is.
+func expand(value string, resolve func(specifier byte) (string, error)) (string, error) {
+	if !(strings.Contains(value, "%")) {
+		return value, nil
+	}
+
+	var builder strings.Builder
+
+	for idx := 0; idx < len(value); idx++ {
+		if value[idx] != '%' || idx+1 >= len(value) {
+			builder.WriteByte(value[idx])
+			continue
+		}
+
+		idx++
+
+		v, e := resolve(value[idx])
+		if e != nil {
+			return "", e
+		}
+
+		builder.WriteString(v)
+	}
+
+	return builder.String(), nil
+}
+
+// Specifiers reports whether the directive's values are subject to specifier expansion (e.g. "%i"). Specifiers are expanded in free-form
+// strings, paths, unit names, lists, commands, environment assignments, conditions, listening addresses and credentials - but not in booleans,
+// numbers, time spans, sizes, modes, signals, limits, calendar events or enumerations.
+func (d Directive) Specifiers() bool {
+	switch d.Type {
+	case TypeBoolean, TypeInteger, TypeTimespan, TypeSize, TypePercent, TypeMode, TypeSignal, TypeLimit, TypeCalendar, TypeEnum:
+		return false
+	}
+
+	return true
+}
+
+// Expander expands the specifiers of unit files, such as "%i" and "%h", as documented by [systemd.unit]. Unit-dependent specifiers (%n, %N, %p,
+// %P, %i, %I, %j, %J, %f, %y, %Y and %d) are derived from the unit's Name and Source; host-dependent specifiers are resolved by the Host.
+//
+// Example:
+//
+//	expander := Expander{Host: Machine{Hostname: "node-1.example.com", Home: "/root"}}
+//	v, e := expander.Expand(daemon, "%h/%l/%i")
+//
+// [systemd.unit]: https://www.freedesktop.org/software/systemd/man/latest/systemd.unit.html#Specifiers
+type Expander struct {
+	Host Host // Resolves host-dependent specifiers; if nil, host-dependent specifiers are rejected. See [LocalMachine].
+}
+
+// resolver returns the specifier resolution of the given unit.
+func (x Expander) resolver(d Daemon) (func(byte) (string, error), error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("unable to expand specifiers of a unit without a name")
+	}
+
+	table, e := specifiers(d.Name)
+	if e != nil {
+		return nil, e
+	}
+
+	return func(specifier byte) (string, error) {
+		if v, ok := table[specifier]; ok {
+			return v, nil
+		}
+
+		switch specifier {
+		case '%':
+			return "%", nil
+		case 'y':
+			return d.Source, nil
+		case 'Y':
+			if d.Source == "" {
+				return "", nil
+			}
+
+			return path.Dir(d.Source), nil
+		}
+
+		if x.Host == nil {
+			return "", fmt.Errorf("unresolved host-dependent specifier: %%%c", specifier)
+		}
+
+		v, e := x.Host.Specifier(specifier)
+		if e == nil && specifier == 'd' {
+			v = path.Join(v, d.Name)
+		}
+
+		return v, e
+	}, nil
+}
+
+// Expand replaces the specifiers of the given value of the unit. An error is returned for unknown specifiers, e.g. "%z".
+func (x Expander) Expand(d Daemon, value string) (string, error) {
+	resolve, e := x.resolver(d)
+	if e != nil {
+		return "", e
+	}
+
+	return expand(value, resolve)
+}
+
+// Apply returns a copy of the unit with the specifiers of each directive value replaced, where the directive supports specifiers (see
+// [Directive.Specifiers]); values of unregistered directives are expanded as well. An error is returned for unknown specifiers.
+func (x Expander) Apply(d Daemon) (*Daemon, error) {
+	resolve, e := x.resolver(d)
+	if e != nil {
+		return nil, e
+	}
+
+	e = d.specialize(func(section string, assignment entry) (string, error) {
+		if directive, ok := Lookup(section, assignment.Key); ok && !(directive.Specifiers()) {
+			return assignment.Value, nil
+		}
+
+		v, e := expand(assignment.Value, resolve)
+		if e != nil {
+			return "", fmt.Errorf("invalid [%s] %s= assignment: %w", section, assignment.Key, e)
+		}
+
+		return v, nil
+	})
+
+	if e != nil {
+		return nil, fmt.Errorf("unable to expand specifiers of %s: %w", d.Name, e)
+	}
+
+	return &d, nil
+}
@@ -0,0 +1,162 @@
+package systemd_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestSpecifiers(t *testing.T) {
+	host := systemd.Machine{
+		Architecture:           "x86-64",
+		Hostname:               "node-1.example.com",
+		MachineID:              "8a7d9e0f1c2b4a5d8e9f0a1b2c3d4e5f",
+		BootID:                 "0f1e2d3c4b5a69788796a5b4c3d2e1f0",
+		KernelRelease:          "6.8.0-generic",
+		Release:                map[string]string{"ID": "debian", "VERSION_ID": "12"},
+		User:                   "root",
+		UID:                    "0",
+		Group:                  "root",
+		GID:                    "0",
+		Home:                   "/root",
+		Shell:                  "/bin/sh",
+		RuntimeDirectory:       "/run",
+		StateDirectory:         "/var/lib",
+		CacheDirectory:         "/var/cache",
+		LogsDirectory:          "/var/log",
+		ConfigurationDirectory: "/etc",
+		DataDirectory:          "/usr/share",
+		CredentialsDirectory:   "/run/credentials",
+	}
+
+	daemon := systemd.Daemon{
+		Name:   `backup@home-alice\x2ddata.service`,
+		Source: "/etc/systemd/system/backup@.service",
+	}
+
+	expander := systemd.Expander{Host: host}
+
+	t.Run("Expand-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"%n":            `backup@home-alice\x2ddata.service`,
+			"%N":            `backup@home-alice\x2ddata`,
+			"%p %P":         "backup backup",
+			"%i":            `home-alice\x2ddata`,
+			"%I":            "home/alice-data",
+			"%f":            "/home/alice-data",
+			"%j":            "backup",
+			"%y":            "/etc/systemd/system/backup@.service",
+			"%Y":            "/etc/systemd/system",
+			"%H %l %q":      "node-1.example.com node-1 node-1.example.com",
+			"%m %b":         "8a7d9e0f1c2b4a5d8e9f0a1b2c3d4e5f 0f1e2d3c4b5a69788796a5b4c3d2e1f0",
+			"%a %v %o %w":   "x86-64 6.8.0-generic debian 12",
+			"%u:%g %U:%G":   "root:root 0:0",
+			"%h %s":         "/root /bin/sh",
+			"%t/%N.sock":    `/run/backup@home-alice\x2ddata.sock`,
+			"%S %C %L":      "/var/lib /var/cache /var/log",
+			"%E %D":         "/etc /usr/share",
+			"%d":            `/run/credentials/backup@home-alice\x2ddata.service`,
+			"%T %V":         "/tmp /var/tmp",
+			"100%% %%i":     "100% %i",
+			"no specifiers": "no specifiers",
+			"trailing %":    "trailing %",
+		}
+
+		for value, expectation := range cases {
+			v, e := expander.Expand(daemon, value)
+			if e != nil {
+				t.Errorf("unexpected error expanding %q: %v", value, e)
+				continue
+			}
+
+			if v != expectation {
+				t.Errorf("unexpected expansion of %q: %q", value, v)
+			}
+		}
+	})
+
+	t.Run("Expand-Unknown-Test", func(t *testing.T) {
+		if _, e := expander.Expand(daemon, "%z"); e == nil {
+			t.Errorf("expected an error for an unknown specifier")
+		}
+	})
+
+	t.Run("Expand-Without-Host-Test", func(t *testing.T) {
+		if v, e := (systemd.Expander{}).Expand(daemon, "%I"); e != nil || v != "home/alice-data" {
+			t.Errorf("unexpected expansion: %q (%v)", v, e)
+		}
+
+		if _, e := (systemd.Expander{}).Expand(daemon, "%h"); e == nil {
+			t.Errorf("expected an error for a host-dependent specifier without a host")
+		}
+
+		if _, e := expander.Expand(systemd.Daemon{}, "%n"); e == nil {
+			t.Errorf("expected an error for a unit without a name")
+		}
+	})
+
+	t.Run("Apply-Test", func(t *testing.T) {
+		unit := daemon
+		unit.Unit = systemd.Unit{Description: "Backup of %f on %H"}
+		unit.Service = systemd.Service{
+			ExecStart:       []string{"/usr/bin/backup --source %f --target %S/backup/%i"},
+			TimeoutStartSec: "%i",
+			Exec: systemd.Exec{
+				WorkingDirectory: "%h",
+			},
+		}
+
+		instance, e := expander.Apply(unit)
+		if e != nil {
+			t.Fatalf("failed expanding unit: %v", e)
+		}
+
+		if instance.Unit.Description != "Backup of /home/alice-data on node-1.example.com" || instance.Service.WorkingDirectory != "/root" {
+			t.Errorf("unexpected expansion: %+v", instance)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStart, []string{`/usr/bin/backup --source /home/alice-data --target /var/lib/backup/home-alice\x2ddata`})) {
+			t.Errorf("unexpected command: %q", instance.Service.ExecStart)
+		}
+
+		// Time spans don't support specifiers.
+		if instance.Service.TimeoutStartSec != "%i" {
+			t.Errorf("unexpected expansion of a time span: %q", instance.Service.TimeoutStartSec)
+		}
+
+		if unit.Service.ExecStart[0] != "/usr/bin/backup --source %f --target %S/backup/%i" {
+			t.Errorf("original unit modified: %q", unit.Service.ExecStart)
+		}
+	})
+
+	t.Run("Directive-Specifiers-Test", func(t *testing.T) {
+		cases := map[string]bool{"ExecStart": true, "WorkingDirectory": true, "Environment": true, "Restart": false, "TimeoutStartSec": false, "NoNewPrivileges": false}
+		for name, expectation := range cases {
+			directive, ok := systemd.Lookup("Service", name)
+			if !(ok) || directive.Specifiers() != expectation {
+				t.Errorf("unexpected specifier support of %s=: %v", name, directive.Specifiers())
+			}
+		}
+	})
+
+	t.Run("Instantiate-Expand-Test", func(t *testing.T) {
+		template := systemd.Daemon{Name: "worker@.service", Service: systemd.Service{Exec: systemd.Exec{Environment: "PROGRESS=100%%h ID=%i HOME=%h"}}}
+
+		instance, e := template.Instantiate("3")
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		instance, e = expander.Apply(*instance)
+		if e != nil || instance.Service.Environment != "PROGRESS=100%h ID=3 HOME=/root" {
+			t.Errorf("unexpected expansion: %q (%v)", instance.Service.Environment, e)
+		}
+	})
+
+	t.Run("Local-Machine-Test", func(t *testing.T) {
+		if m := systemd.LocalMachine(false); m.User != "root" || m.RuntimeDirectory != "/run" || m.Hostname == "" {
+			t.Errorf("unexpected system machine: %+v", m)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package systemd
+
+// Swap represents the [Swap] section of a systemd swap file.
+//
+// A swap unit (e.g. "swapfile.swap") describes a swap device or file controlled by systemd. Swap units must be named after the device or file they
+// control, escaped as systemd-escape --path does; e.g. a unit with What=/dev/disk/by-label/swap must be named "dev-disk-by\x2dlabel-swap.swap". See
+// [UnitName] for checking a unit's name when marshalling.
+//
+//   - The execution environment ([Exec]), process-killing ([Kill]) and resource control ([ResourceControl]) directives apply to the swapon and
+//     swapoff processes spawned for the unit.
+//
+// See [systemd.swap] for additional details.
+//
+// [systemd.swap]: https://www.freedesktop.org/software/systemd/man/latest/systemd.swap.html
+type Swap struct {
+	What       string `json:"What,omitempty" yaml:"What,omitempty" ini:"What,omitempty" systemd:"What,omitempty"`                         // Specifies the absolute path of a device node or file to use for paging.
+	Priority   string `json:"Priority,omitempty" yaml:"Priority,omitempty" ini:"Priority,omitempty" systemd:"Priority,omitempty"`         // Specifies the swap priority to use when activating the swap device or file, between -1 and 32767.
+	Options    string `json:"Options,omitempty" yaml:"Options,omitempty" ini:"Options,omitempty" systemd:"Options,omitempty"`             // Specifies the comma-separated options to use when activating the swap device or file (see swapon(8)'s -o switch).
+	TimeoutSec string `json:"TimeoutSec,omitempty" yaml:"TimeoutSec,omitempty" ini:"TimeoutSec,omitempty" systemd:"TimeoutSec,omitempty"` // Configures the time to wait for the swapon command to finish before it's considered failed and shut down again.
+
+	Exec            `yaml:",inline" ini:",extends"` // Embeds the execution environment directives of systemd.exec(5). See [Exec] for additional details.
+	Kill            `yaml:",inline" ini:",extends"` // Embeds the process-killing directives of systemd.kill(5). See [Kill] for additional details.
+	ResourceControl `yaml:",inline" ini:",extends"` // Embeds the resource control directives of systemd.resource-control(5). See [ResourceControl] for additional details.
+}
@@ -0,0 +1,123 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+// [Unit]
+// Description=Swap File
+//
+// [Swap]
+// What=/var/swap-file
+// Priority=10
+// TimeoutSec=5min
+// StandardOutput=journal
+// KillMode=mixed
+// MemoryMax=64M
+//
+// [Install]
+// WantedBy=swap.target
+func TestSwap(t *testing.T) {
+	daemon := systemd.Daemon{
+		Unit: systemd.Unit{
+			Description: "Swap File",
+		},
+		Install: systemd.Install{
+			WantedBy: "swap.target",
+		},
+		Swap: &systemd.Swap{
+			What:            "/var/swap-file",
+			Priority:        "10",
+			TimeoutSec:      "5min",
+			Exec:            systemd.Exec{StandardOutput: "journal"},
+			Kill:            systemd.Kill{KillMode: systemd.KillModeMixed},
+			ResourceControl: systemd.ResourceControl{MemoryMax: "64M"},
+		},
+	}
+
+	t.Run("Swap-Marshal-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon, systemd.UnitName(`var-swap\x2dfile.swap`))
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"Description=Swap File",
+			"",
+			"[Swap]",
+			"What=/var/swap-file",
+			"Priority=10",
+			"TimeoutSec=5min",
+			"StandardOutput=journal",
+			"KillMode=mixed",
+			"MemoryMax=64M",
+			"",
+			"[Install]",
+			"WantedBy=swap.target",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if !(reflect.DeepEqual(instance.Swap, daemon.Swap)) {
+			t.Errorf("unexpected swap after round-trip: %+v", instance.Swap)
+		}
+	})
+
+	t.Run("Swap-Name-Mismatch-Test", func(t *testing.T) {
+		if _, e := systemd.Marshal(daemon, systemd.UnitName("var-swap-file.swap")); e == nil {
+			t.Errorf("expected an error for a unit name not matching What=")
+		}
+	})
+
+	t.Run("Swap-Legacy-Hierarchy-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon, systemd.LegacyHierarchy())
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		if !(strings.Contains(string(content), "\nMemoryLimit=64M\n")) {
+			t.Errorf("expected MemoryMax= to be rewritten for the legacy hierarchy:\n%s", string(content))
+		}
+	})
+	t.Run("Shared-Directives-Test", func(t *testing.T) {
+		files := map[string]string{
+			"example.socket":  "[Socket]\nListenStream=8080\nUser=example\nMemoryMax=64M\n",
+			"srv-data.mount":  "[Mount]\nWhat=/dev/sdb1\nWhere=/srv/data\nUser=example\nMemoryMax=64M\n",
+			"example.service": "[Service]\nExecStart=/usr/bin/example-agent\nUser=example\nMemoryMax=64M\n",
+		}
+
+		for name, content := range files {
+			instance, e := systemd.Unmarshal([]byte(content), systemd.UnitName(name))
+			if e != nil {
+				t.Fatalf("failed unmarshalling %s: %v", name, e)
+			}
+
+			var exec systemd.Exec
+			var resources systemd.ResourceControl
+			switch {
+			case instance.Socket != nil:
+				exec, resources = instance.Socket.Exec, instance.Socket.ResourceControl
+			case instance.Mount != nil:
+				exec, resources = instance.Mount.Exec, instance.Mount.ResourceControl
+			default:
+				exec, resources = instance.Service.Exec, instance.Service.ResourceControl
+			}
+
+			if exec.User != "example" || resources.MemoryMax != "64M" {
+				t.Errorf("expected the shared directives of %s to be decoded: %+v, %+v", name, exec, resources)
+			}
+		}
+	})
+}
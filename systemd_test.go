@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/poly-gun/systemd"
@@ -43,16 +44,18 @@ func Test(t *testing.T) {
 				PartOf:      "docker.service",
 			},
 			Service: systemd.Service{
-				User:             "steam",
-				Group:            "steam",
-				Type:             "oneshot",
-				RemainAfterExit:  "yes",
-				WorkingDirectory: "/home/steam/.configuration",
-				ExecStartPre:     "/usr/bin/docker compose pull",
-				ExecStart:        "/usr/bin/docker compose up --detach --remove-orphans",
-				ExecStop:         "/usr/bin/docker compose down",
-				StandardOutput:   "journal",
-				StandardError:    "journal",
+				Type:            "oneshot",
+				RemainAfterExit: "yes",
+				ExecStartPre:    []string{"/usr/bin/docker compose pull"},
+				ExecStart:       []string{"/usr/bin/docker compose up --detach --remove-orphans"},
+				ExecStop:        []string{"/usr/bin/docker compose down"},
+				Exec: systemd.Exec{
+					User:             "steam",
+					Group:            "steam",
+					WorkingDirectory: "/home/steam/.configuration",
+					StandardOutput:   "journal",
+					StandardError:    "journal",
+				},
 			},
 			Install: systemd.Install{
 				WantedBy: "multi-user.target docker.service",
@@ -76,11 +79,13 @@ func Test(t *testing.T) {
 				After:         "syslog.target network-online.target",
 			},
 			Service: systemd.Service{
-				Type:           "exec",
-				ExecStart:      "/usr/bin/example-agent",
-				StandardOutput: "journal",
-				StandardError:  "journal",
-				Environment:    "Variable1=value1,Variable2=value2",
+				Type:      "exec",
+				ExecStart: []string{"/usr/bin/example-agent"},
+				Exec: systemd.Exec{
+					StandardOutput: "journal",
+					StandardError:  "journal",
+					Environment:    "Variable1=value1,Variable2=value2",
+				},
 			},
 			Install: systemd.Install{
 				WantedBy: "multi-user.target",
@@ -116,4 +121,90 @@ func Test(t *testing.T) {
 
 		t.Logf("Daemon:\n%+v", instance)
 	})
+	t.Run("Syntax-Unmarshal-Test", func(t *testing.T) {
+		content := []byte(strings.Join([]string{
+			"# Comment",
+			"[Unit]",
+			"Description=Multi-Line \\",
+			"; Ignored Comment",
+			"  Description",
+			"",
+			"[Service]",
+			"ExecStart = /usr/bin/example-agent",
+			"",
+			"[Unit]",
+			"After=network.target",
+		}, "\n"))
+
+		instance, e := systemd.Unmarshal(content)
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Unit.Description != "Multi-Line  Description" {
+			t.Errorf("unexpected description: %q", instance.Unit.Description)
+		}
+
+		if instance.Unit.After != "network.target" {
+			t.Errorf("unexpected after: %q", instance.Unit.After)
+		}
+
+		if len(instance.Service.ExecStart) != 1 || instance.Service.ExecStart[0] != "/usr/bin/example-agent" {
+			t.Errorf("unexpected exec-start: %q", instance.Service.ExecStart)
+		}
+
+		if _, e := systemd.Unmarshal([]byte("Description=Orphan")); e == nil {
+			t.Errorf("expected an error for an assignment outside of a section")
+		}
+	})
+}
+
+func TestUnitRelocation(t *testing.T) {
+	content := []byte(strings.Join([]string{
+		"[Unit]",
+		"Description=Legacy Placement",
+		"StartLimitBurst=10",
+		"",
+		"[Service]",
+		"ExecStart=/usr/bin/example-agent",
+		"FailureAction=reboot",
+		"StartLimitInterval=30s",
+		"StartLimitBurst=3",
+	}, "\n"))
+
+	instance, e := systemd.Unmarshal(content)
+	if e != nil {
+		t.Fatalf("failed unmarshalling daemon: %v", e)
+	}
+
+	if instance.Unit.FailureAction != "reboot" {
+		t.Errorf("expected [Service] FailureAction= to be relocated to [Unit]: %q", instance.Unit.FailureAction)
+	}
+
+	if instance.Unit.StartLimitIntervalSec != "30s" {
+		t.Errorf("expected [Service] StartLimitInterval= to be relocated to [Unit] StartLimitIntervalSec=: %q", instance.Unit.StartLimitIntervalSec)
+	}
+
+	if instance.Unit.StartLimitBurst != "3" {
+		t.Errorf("expected the later [Service] StartLimitBurst= to take precedence: %q", instance.Unit.StartLimitBurst)
+	}
+
+	reordered, e := systemd.Unmarshal([]byte("[Service]\nExecStart=/usr/bin/example-agent\nFailureAction=reboot\n\n[Unit]\nFailureAction=none\n"))
+	if e != nil {
+		t.Fatalf("failed unmarshalling daemon: %v", e)
+	}
+
+	if reordered.Unit.FailureAction != "none" {
+		t.Errorf("expected the later [Unit] FailureAction= to take precedence: %q", reordered.Unit.FailureAction)
+	}
+
+	output, e := systemd.Marshal(*instance)
+	if e != nil {
+		t.Fatalf("failed marshalling daemon: %v", e)
+	}
+
+	unit, service, _ := strings.Cut(string(output), "[Service]")
+	if !(strings.Contains(unit, "FailureAction=reboot\n")) || strings.Contains(service, "FailureAction=") {
+		t.Errorf("expected FailureAction= to be written to [Unit]:\n%s", string(output))
+	}
 }
@@ -0,0 +1,122 @@
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// specialize rewrites each of the Daemon's directive values with the given function. Sections are re-decoded from the rewritten assignments, so
+// the Daemon doesn't share any list or section with its original.
+func (d *Daemon) specialize(rewrite func(section string, assignment entry) (string, error)) error {
+	file, e := d.document()
+	if e != nil {
+		return e
+	}
+
+	for _, g := range file.Groups {
+		for idx := range g.Entries {
+			if g.Entries[idx].Value, e = rewrite(g.Name, g.Entries[idx]); e != nil {
+				return e
+			}
+		}
+	}
+
+	var instance Daemon
+	if e := instance.decode(file); e != nil {
+		return e
+	}
+
+	instance.Name, instance.Kind, instance.Source, instance.DropIns, instance.Provenance, instance.Rewrites = d.Name, d.Kind, d.Source, d.DropIns, d.Provenance, d.Rewrites
+
+	*d = instance
+
+	return nil
+}
+
+// instantiate replaces the specifiers of the Daemon's name (see [specifiers]) in each of its directive values. Other specifiers, including "%%",
+// are kept as-is, so the values remain subject to a later [Expander]. Percent signs of the substituted values (e.g. of an unescaped %I) are
+// escaped as "%%", such that they aren't expanded a second time.
+func (d *Daemon) instantiate() error {
+	table, e := specifiers(d.Name)
+	if e != nil {
+		return e
+	}
+
+	return d.specialize(func(_ string, assignment entry) (string, error) {
+		return expand(assignment.Value, func(specifier byte) (string, error) {
+			if v, ok := table[specifier]; ok {
+				return strings.ReplaceAll(v, "%", "%%"), nil
+			}
+
+			return "%" + string(specifier), nil
+		})
+	})
+}
+
+// Instantiate returns the instance of the given name of a template unit (e.g. "worker@.service"): a copy of the Daemon named after the instance
+// (e.g. "worker@3.service"), with the specifiers of the instance's name - such as %i and %I - replaced in each directive value. Other specifiers,
+// such as %h and "%%", are kept as-is; see [Expander] for expanding them.
+//
+// The instance is expected to be escaped already (e.g. with systemd-escape). An error is returned if the Daemon's Name isn't a template unit name.
+// Instantiate doesn't apply the instance's drop-ins; see [Resolver.Instantiate] for loading an instance along with its drop-ins.
+func (d Daemon) Instantiate(instance string) (*Daemon, error) {
+	v, e := parseName(d.Name)
+	if e != nil {
+		return nil, e
+	}
+
+	if !(v.template) {
+		return nil, fmt.Errorf("unable to instantiate %s: not a template unit", d.Name)
+	}
+
+	if instance == "" {
+		return nil, fmt.Errorf("unable to instantiate %s: empty instance", d.Name)
+	}
+
+	name, e := BuildName(v.prefix, instance, v.kind)
+	if e != nil {
+		return nil, fmt.Errorf("unable to instantiate %s: %w", d.Name, e)
+	}
+
+	d.Name, d.Kind = name, v.kind
+	if e := d.instantiate(); e != nil {
+		return nil, fmt.Errorf("unable to instantiate %s: %w", name, e)
+	}
+
+	return &d, nil
+}
+
+// Instantiate loads the instance of the given name of a template unit (e.g. Instantiate("worker@.service", "3")), as systemd does: the template's
+// unit file, unless the instance has a unit file of its own, with the drop-ins of both the template (e.g. "worker@.service.d") and the instance
+// (e.g. "worker@3.service.d") applied - drop-ins of the instance taking precedence over drop-ins of the same file name of the template. The
+// specifiers of the instance's name are replaced in each directive value; see [Daemon.Instantiate] and [Resolver.Load] for additional details.
+func (r Resolver) Instantiate(template, instance string, settings ...Option) (*Daemon, error) {
+	v, e := parseName(template)
+	if e != nil {
+		return nil, e
+	}
+
+	if !(v.template) {
+		return nil, fmt.Errorf("unable to instantiate %s: not a template unit", template)
+	}
+
+	if instance == "" {
+		return nil, fmt.Errorf("unable to instantiate %s: empty instance", template)
+	}
+
+	name, e := BuildName(v.prefix, instance, v.kind)
+	if e != nil {
+		return nil, fmt.Errorf("unable to instantiate %s: %w", template, e)
+	}
+
+	daemon, e := r.Load(name, settings...)
+	if daemon == nil {
+		return nil, e
+	}
+
+	if e := daemon.instantiate(); e != nil {
+		return nil, fmt.Errorf("unable to instantiate %s: %w", name, e)
+	}
+
+	return daemon, e
+}
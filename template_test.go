@@ -0,0 +1,153 @@
+package systemd_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestTemplate(t *testing.T) {
+	root := t.TempDir()
+
+	write := func(name, content string) {
+		p := filepath.Join(root, name)
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.WriteFile(p, []byte(content), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	write("usr/lib/systemd/system/worker@.service", "[Unit]\nDescription=Worker %I (%n)\n\n[Service]\nExecStart=/usr/bin/worker --id %i --unit %N\nWorkingDirectory=/srv/%p/%i\nEnvironment=PROGRESS=100%%\n")
+	write("usr/lib/systemd/system/worker@.service.d/10-memory.conf", "[Service]\nMemoryMax=1G\n")
+	write("usr/lib/systemd/system/worker@.service.d/20-user.conf", "[Service]\nUser=worker\n")
+	write("etc/systemd/system/worker@3.service.d/20-user.conf", "[Service]\nUser=worker-%i\n")
+	write("etc/systemd/system/worker@3.service.d/30-debug.conf", "[Service]\nExecStart=\nExecStart=/usr/bin/worker --id %i --debug\n")
+
+	resolver := systemd.Resolver{FS: systemd.DirFS(root), Paths: systemd.SystemPaths()}
+
+	t.Run("Resolver-Instantiate-Test", func(t *testing.T) {
+		instance, e := resolver.Instantiate("worker@.service", "3")
+		if e != nil {
+			t.Fatalf("failed instantiating template: %v", e)
+		}
+
+		if instance.Name != "worker@3.service" || instance.Instance() != "3" || instance.Template() {
+			t.Errorf("unexpected identity: %q", instance.Name)
+		}
+
+		if instance.Unit.Description != "Worker 3 (worker@3.service)" {
+			t.Errorf("unexpected description: %q", instance.Unit.Description)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStart, []string{"/usr/bin/worker --id 3 --debug"})) {
+			t.Errorf("unexpected command: %q", instance.Service.ExecStart)
+		}
+
+		if instance.Service.WorkingDirectory != "/srv/worker/3" || instance.Service.Environment != "PROGRESS=100%%" {
+			t.Errorf("unexpected specifier expansion: %q, %q", instance.Service.WorkingDirectory, instance.Service.Environment)
+		}
+
+		// The instance's drop-in shadows the template's drop-in of the same name.
+		if instance.Service.User != "worker-3" || instance.Service.MemoryMax != "1G" {
+			t.Errorf("unexpected drop-ins: %q, %q", instance.Service.User, instance.Service.MemoryMax)
+		}
+
+		expectation := []string{
+			"/usr/lib/systemd/system/worker@.service.d/10-memory.conf",
+			"/etc/systemd/system/worker@3.service.d/20-user.conf",
+			"/etc/systemd/system/worker@3.service.d/30-debug.conf",
+		}
+
+		if !(reflect.DeepEqual(instance.DropIns, expectation)) {
+			t.Errorf("unexpected drop-ins: %v", instance.DropIns)
+		}
+	})
+
+	t.Run("Resolver-Instantiate-Other-Instance-Test", func(t *testing.T) {
+		instance, e := resolver.Instantiate("worker@.service", "4")
+		if e != nil {
+			t.Fatalf("failed instantiating template: %v", e)
+		}
+
+		if instance.Service.User != "worker" || !(reflect.DeepEqual(instance.Service.ExecStart, []string{"/usr/bin/worker --id 4 --unit worker@4"})) {
+			t.Errorf("unexpected instance: %+v", instance.Service)
+		}
+	})
+
+	t.Run("Daemon-Instantiate-Test", func(t *testing.T) {
+		template := systemd.Daemon{
+			Name:    "backup@.service",
+			Unit:    systemd.Unit{Description: "Backup of %f"},
+			Service: systemd.Service{ExecStart: []string{"/usr/bin/backup %I"}},
+		}
+
+		instance, e := template.Instantiate(`home-alice\x2ddata`)
+		if e != nil {
+			t.Fatalf("failed instantiating template: %v", e)
+		}
+
+		if instance.Name != `backup@home-alice\x2ddata.service` || instance.Unit.Description != "Backup of /home/alice-data" {
+			t.Errorf("unexpected instance: %q, %q", instance.Name, instance.Unit.Description)
+		}
+
+		if !(reflect.DeepEqual(instance.Service.ExecStart, []string{"/usr/bin/backup home/alice-data"})) {
+			t.Errorf("unexpected command: %q", instance.Service.ExecStart)
+		}
+
+		// The template is left unchanged.
+		if template.Service.ExecStart[0] != "/usr/bin/backup %I" {
+			t.Errorf("template modified: %q", template.Service.ExecStart)
+		}
+	})
+
+	t.Run("Daemon-Instantiate-Percent-Test", func(t *testing.T) {
+		template := systemd.Daemon{
+			Name:    "throttle@.service",
+			Service: systemd.Service{ExecStart: []string{"/usr/bin/throttle %I"}},
+		}
+
+		instance, e := template.Instantiate(`50\x25off`)
+		if e != nil {
+			t.Fatalf("failed instantiating template: %v", e)
+		}
+
+		// The percent sign of the unescaped instance is escaped, rather than read as the %o specifier by a later expansion.
+		if !(reflect.DeepEqual(instance.Service.ExecStart, []string{"/usr/bin/throttle 50%%off"})) {
+			t.Errorf("unexpected command: %q", instance.Service.ExecStart)
+		}
+
+		expanded, e := (systemd.Expander{}).Apply(*instance)
+		if e != nil {
+			t.Fatalf("failed expanding instance: %v", e)
+		}
+
+		if !(reflect.DeepEqual(expanded.Service.ExecStart, []string{"/usr/bin/throttle 50%off"})) {
+			t.Errorf("unexpected expanded command: %q", expanded.Service.ExecStart)
+		}
+	})
+
+	t.Run("Instantiate-Invalid-Test", func(t *testing.T) {
+		if _, e := (systemd.Daemon{Name: "worker.service"}).Instantiate("3"); e == nil {
+			t.Errorf("expected an error instantiating a unit other than a template")
+		}
+
+		if _, e := (systemd.Daemon{Name: "worker@.service"}).Instantiate("a/b"); e == nil {
+			t.Errorf("expected an error for an invalid instance")
+		}
+
+		if _, e := resolver.Instantiate("worker@.service", ""); e == nil {
+			t.Errorf("expected an error for an empty instance")
+		}
+
+		if _, e := resolver.Instantiate("worker@.service", "a/b"); e == nil || errors.Unwrap(e) == nil {
+			t.Errorf("expected a wrapped error for an invalid instance: %v", e)
+		}
+	})
+}
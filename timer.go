@@ -0,0 +1,30 @@
+package systemd
+
+// Timer represents the [Timer] section of a systemd timer file.
+//
+// A timer unit (e.g. "example.timer") activates another unit, by default the service unit of the same name (e.g. "example.service"), based on
+// monotonic (relative) or realtime (calendar) time events. The [Timer] section describes when, and how accurately, the activation takes place.
+//
+//   - OnCalendar may be specified more than once; the timer elapses on any of the listed calendar events. An empty assignment resets the list.
+//
+// See [systemd.timer] for additional details, and [systemd.time] for the time span and calendar event syntax.
+//
+// [systemd.timer]: https://www.freedesktop.org/software/systemd/man/latest/systemd.timer.html
+// [systemd.time]: https://www.freedesktop.org/software/systemd/man/latest/systemd.time.html
+type Timer struct {
+	OnActiveSec        string   `json:"OnActiveSec,omitempty" yaml:"OnActiveSec,omitempty" ini:"OnActiveSec,omitempty" systemd:"OnActiveSec,omitempty"`                             // Defines a timer relative to the moment the timer unit itself is activated.
+	OnBootSec          string   `json:"OnBootSec,omitempty" yaml:"OnBootSec,omitempty" ini:"OnBootSec,omitempty" systemd:"OnBootSec,omitempty"`                                     // Defines a timer relative to when the machine was booted up.
+	OnStartupSec       string   `json:"OnStartupSec,omitempty" yaml:"OnStartupSec,omitempty" ini:"OnStartupSec,omitempty" systemd:"OnStartupSec,omitempty"`                         // Defines a timer relative to when the service manager was first started.
+	OnUnitActiveSec    string   `json:"OnUnitActiveSec,omitempty" yaml:"OnUnitActiveSec,omitempty" ini:"OnUnitActiveSec,omitempty" systemd:"OnUnitActiveSec,omitempty"`             // Defines a timer relative to when the unit the timer activates was last activated.
+	OnUnitInactiveSec  string   `json:"OnUnitInactiveSec,omitempty" yaml:"OnUnitInactiveSec,omitempty" ini:"OnUnitInactiveSec,omitempty" systemd:"OnUnitInactiveSec,omitempty"`     // Defines a timer relative to when the unit the timer activates was last deactivated.
+	OnCalendar         []string `json:"OnCalendar,omitempty" yaml:"OnCalendar,omitempty" ini:"-" systemd:"OnCalendar,omitempty"`                                                    // Defines realtime (i.e. wallclock) timers with calendar event expressions, e.g. "Mon..Fri *-*-* 09:00:00".
+	AccuracySec        string   `json:"AccuracySec,omitempty" yaml:"AccuracySec,omitempty" ini:"AccuracySec,omitempty" systemd:"AccuracySec,omitempty"`                             // Specifies the accuracy the timer shall elapse with. Defaults to 1min.
+	RandomizedDelaySec string   `json:"RandomizedDelaySec,omitempty" yaml:"RandomizedDelaySec,omitempty" ini:"RandomizedDelaySec,omitempty" systemd:"RandomizedDelaySec,omitempty"` // Delays the timer by a randomly selected, evenly distributed amount of time between 0 and the specified time value.
+	FixedRandomDelay   string   `json:"FixedRandomDelay,omitempty" yaml:"FixedRandomDelay,omitempty" ini:"FixedRandomDelay,omitempty" systemd:"FixedRandomDelay,omitempty"`         // If true, the randomized delay is stable between runs of the service manager on the same machine.
+	OnClockChange      string   `json:"OnClockChange,omitempty" yaml:"OnClockChange,omitempty" ini:"OnClockChange,omitempty" systemd:"OnClockChange,omitempty"`                     // If true, the unit is triggered when the system clock (CLOCK_REALTIME) jumps relative to the monotonic clock.
+	OnTimezoneChange   string   `json:"OnTimezoneChange,omitempty" yaml:"OnTimezoneChange,omitempty" ini:"OnTimezoneChange,omitempty" systemd:"OnTimezoneChange,omitempty"`         // If true, the unit is triggered when the local system timezone is modified.
+	Unit               string   `json:"Unit,omitempty" yaml:"Unit,omitempty" ini:"Unit,omitempty" systemd:"Unit,omitempty"`                                                         // Specifies the unit to activate when the timer elapses. Defaults to a service with the same name as the timer, except for the suffix.
+	Persistent         string   `json:"Persistent,omitempty" yaml:"Persistent,omitempty" ini:"Persistent,omitempty" systemd:"Persistent,omitempty"`                                 // If true, the time the unit was last triggered is stored on disk, and a missed OnCalendar= run is triggered immediately when the timer is activated.
+	WakeSystem         string   `json:"WakeSystem,omitempty" yaml:"WakeSystem,omitempty" ini:"WakeSystem,omitempty" systemd:"WakeSystem,omitempty"`                                 // If true, an elapsing timer resumes the system from suspend, should it be suspended and if the system supports this.
+	RemainAfterElapse  string   `json:"RemainAfterElapse,omitempty" yaml:"RemainAfterElapse,omitempty" ini:"RemainAfterElapse,omitempty" systemd:"RemainAfterElapse,omitempty"`     // If true (default), an elapsed timer stays loaded, and its state remains queryable.
+}
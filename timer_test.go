@@ -0,0 +1,88 @@
+package systemd_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+// [Unit]
+// Description=Nightly Backup
+//
+// [Timer]
+// OnCalendar=Mon..Fri *-*-* 02:00:00
+// OnCalendar=Sat,Sun *-*-* 04:00:00
+// RandomizedDelaySec=15min
+// Unit=backup.service
+// Persistent=true
+//
+// [Install]
+// WantedBy=timers.target
+func TestTimer(t *testing.T) {
+	daemon := systemd.Daemon{
+		Unit: systemd.Unit{
+			Description: "Nightly Backup",
+		},
+		Install: systemd.Install{
+			WantedBy: "timers.target",
+		},
+		Timer: &systemd.Timer{
+			OnCalendar:         []string{"Mon..Fri *-*-* 02:00:00", "Sat,Sun *-*-* 04:00:00"},
+			RandomizedDelaySec: "15min",
+			Unit:               "backup.service",
+			Persistent:         "true",
+		},
+	}
+
+	t.Run("Timer-Marshal-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		expectation := strings.Join([]string{
+			"[Unit]",
+			"Description=Nightly Backup",
+			"",
+			"[Timer]",
+			"OnCalendar=Mon..Fri *-*-* 02:00:00",
+			"OnCalendar=Sat,Sun *-*-* 04:00:00",
+			"RandomizedDelaySec=15min",
+			"Unit=backup.service",
+			"Persistent=true",
+			"",
+			"[Install]",
+			"WantedBy=timers.target",
+		}, "\n")
+
+		if string(content) != expectation {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+	})
+
+	t.Run("Timer-Unmarshal-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		instance, e := systemd.Unmarshal(append(content, []byte("\n[Timer]\nOnCalendar=\nOnCalendar=daily\n")...))
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Timer == nil {
+			t.Fatalf("expected a [Timer] section")
+		}
+
+		if !(reflect.DeepEqual(instance.Timer.OnCalendar, []string{"daily"})) {
+			t.Errorf("expected the empty assignment to reset OnCalendar=: %q", instance.Timer.OnCalendar)
+		}
+
+		if instance.Timer.Unit != "backup.service" || instance.Timer.Persistent != "true" {
+			t.Errorf("unexpected timer: %+v", instance.Timer)
+		}
+	})
+}
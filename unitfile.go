@@ -0,0 +1,345 @@
+package systemd
+
+import (
+	"fmt"
+)
+
+// UnitFile represents a unit file of a single [Kind], carrying only the sections that apply to its unit type. It's implemented by each of the
+// concrete unit kinds, and as such, can be type-switched over:
+//
+//   - [ServiceUnit], [SocketUnit], [TimerUnit], [PathUnit], [MountUnit], [AutomountUnit], [SwapUnit] and [SliceUnit] carry the [Unit] and
+//     [Install] sections, along with their type-specific section.
+//   - [TargetUnit] and [DeviceUnit] carry the [Unit] and [Install] sections only, as neither unit type has a type-specific section.
+//   - [ScopeUnit] carries the [Unit] and [Scope] sections; scopes are created at runtime, and can't be enabled.
+//
+// See [Daemon.UnitFile] for converting a [Daemon], and [UnmarshalUnitFile] and [MarshalUnitFile] for decoding and encoding unit files.
+type UnitFile interface {
+	// Kind returns the unit file's type.
+	Kind() Kind
+
+	// Daemon returns the generic representation of the unit file, as accepted by [Marshal].
+	Daemon() Daemon
+}
+
+// ServiceUnit represents a service unit file, e.g. "example.service".
+type ServiceUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Service Service `json:"Service" yaml:"Service"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *ServiceUnit) Kind() Kind {
+	return KindService
+}
+
+func (u *ServiceUnit) Daemon() Daemon {
+	return Daemon{Name: u.Name, Kind: KindService, Unit: u.Unit, Service: u.Service, Install: u.Install}
+}
+
+// SocketUnit represents a socket unit file, e.g. "example.socket".
+type SocketUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Socket  Socket  `json:"Socket" yaml:"Socket"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *SocketUnit) Kind() Kind {
+	return KindSocket
+}
+
+func (u *SocketUnit) Daemon() Daemon {
+	socket := u.Socket
+
+	return Daemon{Name: u.Name, Kind: KindSocket, Unit: u.Unit, Socket: &socket, Install: u.Install}
+}
+
+// TimerUnit represents a timer unit file, e.g. "example.timer".
+type TimerUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Timer   Timer   `json:"Timer" yaml:"Timer"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *TimerUnit) Kind() Kind {
+	return KindTimer
+}
+
+func (u *TimerUnit) Daemon() Daemon {
+	timer := u.Timer
+
+	return Daemon{Name: u.Name, Kind: KindTimer, Unit: u.Unit, Timer: &timer, Install: u.Install}
+}
+
+// PathUnit represents a path unit file, e.g. "example.path".
+type PathUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Path    Path    `json:"Path" yaml:"Path"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *PathUnit) Kind() Kind {
+	return KindPath
+}
+
+func (u *PathUnit) Daemon() Daemon {
+	p := u.Path
+
+	return Daemon{Name: u.Name, Kind: KindPath, Unit: u.Unit, Path: &p, Install: u.Install}
+}
+
+// MountUnit represents a mount unit file, e.g. "srv-data.mount". Mount units must be named after their escaped Where= path.
+type MountUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Mount   Mount   `json:"Mount" yaml:"Mount"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *MountUnit) Kind() Kind {
+	return KindMount
+}
+
+func (u *MountUnit) Daemon() Daemon {
+	mount := u.Mount
+
+	return Daemon{Name: u.Name, Kind: KindMount, Unit: u.Unit, Mount: &mount, Install: u.Install}
+}
+
+// AutomountUnit represents an automount unit file, e.g. "srv-data.automount". Automount units must be named after their escaped Where= path.
+type AutomountUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit      Unit      `json:"Unit" yaml:"Unit"`
+	Automount Automount `json:"Automount" yaml:"Automount"`
+	Install   Install   `json:"Install" yaml:"Install"`
+}
+
+func (u *AutomountUnit) Kind() Kind {
+	return KindAutomount
+}
+
+func (u *AutomountUnit) Daemon() Daemon {
+	automount := u.Automount
+
+	return Daemon{Name: u.Name, Kind: KindAutomount, Unit: u.Unit, Automount: &automount, Install: u.Install}
+}
+
+// SwapUnit represents a swap unit file, e.g. "dev-sdb2.swap". Swap units must be named after their escaped What= path.
+type SwapUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Swap    Swap    `json:"Swap" yaml:"Swap"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *SwapUnit) Kind() Kind {
+	return KindSwap
+}
+
+func (u *SwapUnit) Daemon() Daemon {
+	swap := u.Swap
+
+	return Daemon{Name: u.Name, Kind: KindSwap, Unit: u.Unit, Swap: &swap, Install: u.Install}
+}
+
+// SliceUnit represents a slice unit file, e.g. "tenant-a.slice".
+type SliceUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Slice   Slice   `json:"Slice" yaml:"Slice"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *SliceUnit) Kind() Kind {
+	return KindSlice
+}
+
+func (u *SliceUnit) Daemon() Daemon {
+	slice := u.Slice
+
+	return Daemon{Name: u.Name, Kind: KindSlice, Unit: u.Unit, Slice: &slice, Install: u.Install}
+}
+
+// ScopeUnit represents a scope unit, e.g. "session-1.scope". Scopes are created at runtime (see [Transient]) rather than loaded from a unit file,
+// but may still be configured through drop-ins.
+type ScopeUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit  Unit  `json:"Unit" yaml:"Unit"`
+	Scope Scope `json:"Scope" yaml:"Scope"`
+}
+
+func (u *ScopeUnit) Kind() Kind {
+	return KindScope
+}
+
+func (u *ScopeUnit) Daemon() Daemon {
+	scope := u.Scope
+
+	return Daemon{Name: u.Name, Kind: KindScope, Unit: u.Unit, Scope: &scope}
+}
+
+// TargetUnit represents a target unit file, e.g. "multi-user.target".
+type TargetUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *TargetUnit) Kind() Kind {
+	return KindTarget
+}
+
+func (u *TargetUnit) Daemon() Daemon {
+	return Daemon{Name: u.Name, Kind: KindTarget, Unit: u.Unit, Install: u.Install}
+}
+
+// DeviceUnit represents a device unit, e.g. "dev-sda.device". Devices are exposed by udev rather than loaded from a unit file, but may still be
+// configured through drop-ins.
+type DeviceUnit struct {
+	Name string `json:"Name,omitempty" yaml:"Name,omitempty"` // Specifies the unit's file name. See [UnitName] for additional details.
+
+	Unit    Unit    `json:"Unit" yaml:"Unit"`
+	Install Install `json:"Install" yaml:"Install"`
+}
+
+func (u *DeviceUnit) Kind() Kind {
+	return KindDevice
+}
+
+func (u *DeviceUnit) Daemon() Daemon {
+	return Daemon{Name: u.Name, Kind: KindDevice, Unit: u.Unit, Install: u.Install}
+}
+
+// UnitFile returns the Daemon as the concrete [UnitFile] of its unit type. The unit type is taken from the Daemon's Kind, its Name, or otherwise its
+// type-specific section, and the Daemon's sections are checked against it. A Daemon without a type-specific section, and without a Kind or Name,
+// is considered a service.
+//
+// The Daemon's Source, DropIns, Provenance and Rewrites aren't carried over.
+func (d *Daemon) UnitFile() (UnitFile, error) {
+	kind, e := resolve(d.Kind, options{name: d.Name})
+	if e != nil {
+		return nil, e
+	}
+
+	file, e := d.document()
+	if e != nil {
+		return nil, e
+	}
+
+	if kind == "" {
+		if kind, e = detect(file); e != nil {
+			return nil, e
+		}
+
+		if kind == "" {
+			kind = KindService
+		}
+	}
+
+	if e := conform(file, kind); e != nil {
+		return nil, fmt.Errorf("unable to convert %s unit: %w", kind, e)
+	}
+
+	switch kind {
+	case KindService:
+		return &ServiceUnit{Name: d.Name, Unit: d.Unit, Service: d.Service, Install: d.Install}, nil
+	case KindSocket:
+		u := &SocketUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Socket != nil {
+			u.Socket = *d.Socket
+		}
+
+		return u, nil
+	case KindTimer:
+		u := &TimerUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Timer != nil {
+			u.Timer = *d.Timer
+		}
+
+		return u, nil
+	case KindPath:
+		u := &PathUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Path != nil {
+			u.Path = *d.Path
+		}
+
+		return u, nil
+	case KindMount:
+		u := &MountUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Mount != nil {
+			u.Mount = *d.Mount
+		}
+
+		return u, nil
+	case KindAutomount:
+		u := &AutomountUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Automount != nil {
+			u.Automount = *d.Automount
+		}
+
+		return u, nil
+	case KindSwap:
+		u := &SwapUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Swap != nil {
+			u.Swap = *d.Swap
+		}
+
+		return u, nil
+	case KindSlice:
+		u := &SliceUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}
+		if d.Slice != nil {
+			u.Slice = *d.Slice
+		}
+
+		return u, nil
+	case KindScope:
+		u := &ScopeUnit{Name: d.Name, Unit: d.Unit}
+		if d.Scope != nil {
+			u.Scope = *d.Scope
+		}
+
+		return u, nil
+	case KindTarget:
+		return &TargetUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}, nil
+	case KindDevice:
+		return &DeviceUnit{Name: d.Name, Unit: d.Unit, Install: d.Install}, nil
+	}
+
+	return nil, fmt.Errorf("unknown unit type: %q", kind)
+}
+
+// UnmarshalUnitFile decodes a unit file of any [Kind] into its concrete [UnitFile]. The unit type is derived from the configured unit name (see
+// [UnitName]) or, otherwise, from the unit's type-specific section; see [Unmarshal] and [Daemon.UnitFile] for additional details.
+//
+// As with [Unmarshal], the decoded unit file is still returned alongside a [CompatibilityError].
+func UnmarshalUnitFile(stream []byte, settings ...Option) (UnitFile, error) {
+	instance, e := Unmarshal(stream, settings...)
+	if instance == nil {
+		return nil, e
+	}
+
+	u, exception := instance.UnitFile()
+	if exception != nil {
+		return nil, exception
+	}
+
+	return u, e
+}
+
+// MarshalUnitFile renders the [UnitFile] as a systemd unit file; see [Marshal] for additional details.
+func MarshalUnitFile(u UnitFile, settings ...Option) ([]byte, error) {
+	return Marshal(u.Daemon(), settings...)
+}
@@ -0,0 +1,104 @@
+package systemd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestUnitFile(t *testing.T) {
+	t.Run("Unit-File-Kinds-Test", func(t *testing.T) {
+		cases := map[string]string{
+			"example.service":    "[Unit]\nDescription=Example\n\n[Service]\nExecStart=/usr/bin/example\n",
+			"example.socket":     "[Socket]\nListenStream=8080\n",
+			"backup.timer":       "[Timer]\nOnCalendar=daily\n",
+			"upload.path":        "[Path]\nPathChanged=/srv/upload\n",
+			"srv-data.mount":     "[Mount]\nWhat=/dev/sdb1\nWhere=/srv/data\n",
+			"srv-data.automount": "[Automount]\nWhere=/srv/data\n",
+			"dev-sdb2.swap":      "[Swap]\nWhat=/dev/sdb2\n",
+			"tenant-a.slice":     "[Slice]\nMemoryMax=4G\n",
+			"session-1.scope":    "[Scope]\nRuntimeMaxSec=1h\n",
+			"stack.target":       "[Unit]\nDescription=Application Stack\n\n[Install]\nWantedBy=multi-user.target\n",
+			"dev-sda.device":     "[Unit]\nDescription=Disk\n",
+		}
+
+		for name, content := range cases {
+			u, e := systemd.UnmarshalUnitFile([]byte(content), systemd.UnitName(name))
+			if e != nil {
+				t.Errorf("failed unmarshalling %s: %v", name, e)
+				continue
+			}
+
+			kind, _ := systemd.KindOf(name)
+			if u.Kind() != kind {
+				t.Errorf("unexpected kind of %s: %q", name, u.Kind())
+			}
+
+			output, e := systemd.MarshalUnitFile(u)
+			if e != nil {
+				t.Errorf("failed marshalling %s: %v", name, e)
+				continue
+			}
+
+			for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+				if line != "" && !(strings.Contains(string(output), line)) {
+					t.Errorf("expected %q in marshalled %s:\n%s", line, name, string(output))
+				}
+			}
+		}
+	})
+
+	t.Run("Unit-File-Type-Switch-Test", func(t *testing.T) {
+		u, e := systemd.UnmarshalUnitFile([]byte("[Unit]\nDescription=Backup\n\n[Timer]\nOnCalendar=daily\n"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling unit file: %v", e)
+		}
+
+		switch instance := u.(type) {
+		case *systemd.TimerUnit:
+			if len(instance.Timer.OnCalendar) != 1 || instance.Unit.Description != "Backup" {
+				t.Errorf("unexpected timer unit: %+v", instance)
+			}
+		default:
+			t.Errorf("expected a timer unit: %T", u)
+		}
+
+		u, e = systemd.UnmarshalUnitFile([]byte("[Unit]\nDescription=Example\n\n[Service]\nExecStart=/usr/bin/example\n"))
+		if e != nil {
+			t.Fatalf("failed unmarshalling unit file: %v", e)
+		}
+
+		if _, ok := u.(*systemd.ServiceUnit); !(ok) {
+			t.Errorf("expected a service unit: %T", u)
+		}
+	})
+
+	t.Run("Unit-File-Mismatch-Test", func(t *testing.T) {
+		if _, e := systemd.UnmarshalUnitFile([]byte("[Service]\nExecStart=/usr/bin/example\n"), systemd.UnitName("stack.target")); e == nil {
+			t.Errorf("expected an error for a [Service] section in a target unit")
+		}
+
+		daemon := systemd.Daemon{Kind: systemd.KindDevice, Service: systemd.Service{ExecStart: []string{"/usr/bin/example"}}}
+		if _, e := daemon.UnitFile(); e == nil {
+			t.Errorf("expected an error for a [Service] section in a device unit")
+		}
+	})
+
+	t.Run("Unit-File-Target-Marshal-Test", func(t *testing.T) {
+		target := &systemd.TargetUnit{
+			Name:    "stack.target",
+			Unit:    systemd.Unit{Description: "Application Stack"},
+			Install: systemd.Install{WantedBy: "multi-user.target"},
+		}
+
+		output, e := systemd.MarshalUnitFile(target)
+		if e != nil {
+			t.Fatalf("failed marshalling target: %v", e)
+		}
+
+		if strings.Contains(string(output), "[Service]") {
+			t.Errorf("expected a target unit without a [Service] section:\n%s", string(output))
+		}
+	})
+}
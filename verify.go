@@ -0,0 +1,401 @@
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity represents the severity of a [Diagnostic].
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // The unit fails to load or start, as is.
+	SeverityWarning Severity = "warning" // The unit loads, but likely doesn't behave as intended; e.g. a missing Wants= dependency.
+)
+
+// Category represents the kind of verification a [Diagnostic] resulted from.
+type Category string
+
+const (
+	CategoryLoad       Category = "load"       // The unit can't be found, read, or parsed; or it's masked.
+	CategoryType       Category = "type"       // The unit's type-specific section doesn't match its type; e.g. a [Timer] section in a service unit.
+	CategoryExecutable Category = "executable" // A command's executable doesn't exist, or isn't executable.
+	CategoryDependency Category = "dependency" // A unit referenced by a dependency (e.g. Requires=) or activation (e.g. a timer's Unit=) doesn't exist.
+	CategoryUser       Category = "user"       // The User= doesn't exist.
+	CategoryGroup      Category = "group"      // The Group= or a SupplementaryGroups= group doesn't exist.
+)
+
+// Diagnostic represents a single finding of a [Verifier], along with the location of the offending assignment, if any.
+type Diagnostic struct {
+	Unit      string   `json:"Unit" yaml:"Unit"`                               // Specifies the verified unit's name, e.g. "example.service".
+	Severity  Severity `json:"Severity" yaml:"Severity"`                       // Specifies the diagnostic's severity.
+	Category  Category `json:"Category" yaml:"Category"`                       // Specifies the kind of verification the diagnostic resulted from.
+	Section   string   `json:"Section,omitempty" yaml:"Section,omitempty"`     // Specifies the offending directive's section, if any.
+	Directive string   `json:"Directive,omitempty" yaml:"Directive,omitempty"` // Specifies the offending directive, if any.
+	Value     string   `json:"Value,omitempty" yaml:"Value,omitempty"`         // Specifies the offending value, e.g. the missing executable or unit.
+	File      string   `json:"File,omitempty" yaml:"File,omitempty"`           // Specifies the path of the file the offending directive was assigned in, relative to the verified root.
+	Line      int      `json:"Line,omitempty" yaml:"Line,omitempty"`           // Specifies the line the offending directive was assigned on.
+	Message   string   `json:"Message" yaml:"Message"`                         // Provides a human-readable description of the finding.
+}
+
+// String returns the diagnostic in the form of systemd-analyze verify, e.g.
+// "/etc/systemd/system/example.service:7: [Service] ExecStart=: command /usr/bin/example is not executable: no such file".
+func (d Diagnostic) String() string {
+	var builder strings.Builder
+
+	switch {
+	case d.File != "" && d.Line > 0:
+		builder.WriteString(fmt.Sprintf("%s:%d: ", d.File, d.Line))
+	case d.File != "":
+		builder.WriteString(d.File + ": ")
+	default:
+		builder.WriteString(d.Unit + ": ")
+	}
+
+	if d.Directive != "" {
+		builder.WriteString(fmt.Sprintf("[%s] %s=: ", d.Section, d.Directive))
+	}
+
+	builder.WriteString(d.Message)
+
+	return builder.String()
+}
+
+// requirements specifies the dependency directives of the [Unit] section whose units must exist, and the severity of missing ones. Ordering
+// (e.g. After=) and negative (Conflicts=) dependencies don't require the referenced units to exist.
+var requirements = map[string]Severity{
+	"Wants":     SeverityWarning,
+	"Requires":  SeverityError,
+	"Requisite": SeverityError,
+	"BindsTo":   SeverityError,
+	"PartOf":    SeverityError,
+	"Upholds":   SeverityWarning,
+	"OnFailure": SeverityError,
+	"OnSuccess": SeverityError,
+}
+
+// executables specifies the directories systemd searches for commands without an absolute path, in order.
+var executables = []string{"/usr/local/sbin", "/usr/local/bin", "/usr/sbin", "/usr/bin", "/sbin", "/bin"}
+
+// Verifier verifies units offline - against a root directory rather than the running system - as systemd-analyze verify --root does. Units are
+// loaded through the Resolver's search path, along with their drop-ins, and verified for:
+//
+//   - Their type-specific section matching their type (see [Kind]).
+//   - The executables of their commands (e.g. ExecStart=) existing, and being executable, within the root.
+//   - The units they require (e.g. Requires= and Wants=), and activate (e.g. a timer's Unit=, or a socket's Service=), existing.
+//   - Their User=, Group= and SupplementaryGroups= existing in the root's /etc/passwd and /etc/group, unless DynamicUser= is enabled.
+//
+// Example:
+//
+//	verifier := Verifier{Resolver: Resolver{FS: DirFS("/srv/image"), Paths: SystemPaths()}}
+//	diagnostics, e := verifier.Verify()
+type Verifier struct {
+	Resolver
+
+	Host Host // Resolves host-dependent specifiers within commands and unit names, if any; see [Expander].
+}
+
+// Verify verifies the units of the given names below the root directory, or, if no names are given, every unit of the system search path (see
+// [SystemPaths]). See [Verifier] for additional details.
+func Verify(root string, names ...string) ([]Diagnostic, error) {
+	return Verifier{Resolver: Resolver{FS: DirFS(root), Paths: SystemPaths()}}.Verify(names...)
+}
+
+// Units returns the names of the units of the search path, sorted: unit files and symbolic links, with the exception of template units (e.g.
+// "getty@.service"), which can't be verified without an instance, and of masked units and aliases.
+func (v Verifier) Units() ([]string, error) {
+	found := make(map[string]bool)
+	for _, directory := range v.Paths {
+		entries, e := fs.ReadDir(v.FS, relative(directory))
+		if errors.Is(e, fs.ErrNotExist) {
+			continue
+		} else if e != nil {
+			return nil, e
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			if identity, e := parseName(entry.Name()); e == nil && !(identity.template) {
+				found[entry.Name()] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		resolution, e := v.Resolve(name)
+		if e == nil && (resolution.Masked || len(resolution.Aliases) > 0) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Verify verifies the units of the given names, or, if no names are given, every unit of the search path (see [Verifier.Units]). Findings are
+// returned as diagnostics, in the order of the given units; an error is only returned if the search path can't be read.
+func (v Verifier) Verify(names ...string) ([]Diagnostic, error) {
+	if len(names) == 0 {
+		units, e := v.Units()
+		if e != nil {
+			return nil, e
+		}
+
+		names = units
+	}
+
+	users, groups := v.accounts()
+
+	diagnostics := make([]Diagnostic, 0)
+	for _, name := range names {
+		diagnostics = append(diagnostics, v.unit(name, users, groups)...)
+	}
+
+	return diagnostics, nil
+}
+
+// accounts returns the user and group names of the root's /etc/passwd and /etc/group, along with the "root" and "nobody" accounts systemd
+// always knows of.
+func (v Verifier) accounts() (map[string]bool, map[string]bool) {
+	read := func(p string) map[string]bool {
+		names := map[string]bool{"root": true, "nobody": true}
+
+		content, e := fs.ReadFile(v.FS, p)
+		if e != nil {
+			return names
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			if name, _, ok := strings.Cut(scanner.Text(), ":"); ok && name != "" && !(strings.HasPrefix(name, "#")) {
+				names[name] = true
+			}
+		}
+
+		return names
+	}
+
+	return read("etc/passwd"), read("etc/group")
+}
+
+// unit verifies a single unit; see [Verifier].
+func (v Verifier) unit(name string, users, groups map[string]bool) []Diagnostic {
+	daemon, e := v.Load(name)
+
+	var compatibility *CompatibilityError
+	if e != nil && !(errors.As(e, &compatibility)) {
+		var section *mismatch
+		switch {
+		case errors.As(e, &section):
+			return []Diagnostic{{Unit: name, Severity: SeverityError, Category: CategoryType, Message: e.Error()}}
+		case errors.Is(e, ErrMasked):
+			return []Diagnostic{{Unit: name, Severity: SeverityWarning, Category: CategoryLoad, Message: "unit is masked"}}
+		}
+
+		return []Diagnostic{{Unit: name, Severity: SeverityError, Category: CategoryLoad, Message: e.Error()}}
+	}
+
+	file, e := daemon.document()
+	if e != nil {
+		return []Diagnostic{{Unit: name, Severity: SeverityError, Category: CategoryLoad, File: daemon.Source, Message: e.Error()}}
+	}
+
+	expander := Expander{Host: v.Host}
+
+	diagnostics := make([]Diagnostic, 0)
+	report := func(severity Severity, category Category, section, directive, value, message string) {
+		p := Diagnostic{Unit: name, Severity: severity, Category: category, Section: section, Directive: directive, Value: value, Message: message}
+		p.File, p.Line = locate(daemon, section, directive, value)
+
+		diagnostics = append(diagnostics, p)
+	}
+
+	for _, g := range file.Groups {
+		dynamic := false
+		for _, assignment := range g.Entries {
+			if assignment.Key == "DynamicUser" {
+				dynamic, _ = boolean(assignment.Value)
+			}
+		}
+
+		for _, assignment := range g.Entries {
+			directive, _ := Lookup(g.Name, assignment.Key)
+
+			switch {
+			case directive.Type == TypeCommand:
+				if severity, message := v.command(expander, *daemon, assignment.Value); message != "" {
+					report(severity, CategoryExecutable, g.Name, assignment.Key, assignment.Value, message)
+				}
+			case g.Name == "Unit" && requirements[assignment.Key] != "":
+				for _, dependency := range strings.Fields(assignment.Value) {
+					if message := v.dependency(expander, *daemon, dependency); message != "" {
+						report(requirements[assignment.Key], CategoryDependency, g.Name, assignment.Key, dependency, message)
+					}
+				}
+			case (g.Name == "Timer" || g.Name == "Path") && assignment.Key == "Unit", g.Name == "Socket" && assignment.Key == "Service":
+				if message := v.dependency(expander, *daemon, assignment.Value); message != "" {
+					report(SeverityError, CategoryDependency, g.Name, assignment.Key, assignment.Value, message)
+				}
+			case g.Name == "Install" && assignment.Key == "Also":
+				for _, dependency := range strings.Fields(assignment.Value) {
+					if message := v.dependency(expander, *daemon, dependency); message != "" {
+						report(SeverityWarning, CategoryDependency, g.Name, assignment.Key, dependency, message)
+					}
+				}
+			case assignment.Key == "User" && !(dynamic) && !(known(users, assignment.Value)):
+				report(SeverityError, CategoryUser, g.Name, assignment.Key, assignment.Value, fmt.Sprintf("user %s doesn't exist", assignment.Value))
+			case assignment.Key == "Group" && !(dynamic) && !(known(groups, assignment.Value)):
+				report(SeverityError, CategoryGroup, g.Name, assignment.Key, assignment.Value, fmt.Sprintf("group %s doesn't exist", assignment.Value))
+			case assignment.Key == "SupplementaryGroups":
+				for _, group := range strings.Fields(assignment.Value) {
+					if !(known(groups, group)) {
+						report(SeverityError, CategoryGroup, g.Name, assignment.Key, group, fmt.Sprintf("group %s doesn't exist", group))
+					}
+				}
+			}
+		}
+	}
+
+	// Timers, paths and sockets without an explicit unit activate the service of the same name.
+	if message := v.activation(expander, *daemon); message != "" {
+		report(SeverityError, CategoryDependency, daemon.Kind.Section(), "", "", message)
+	}
+
+	return diagnostics
+}
+
+// known reports whether the given user or group name (or numeric ID) is known. Names containing specifiers aren't verified.
+func known(names map[string]bool, name string) bool {
+	if _, e := strconv.ParseUint(name, 10, 32); e == nil {
+		return true
+	}
+
+	return names[name] || strings.Contains(name, "%")
+}
+
+// locate returns the file and line of the last assignment of the given directive containing the value, per the Daemon's provenance.
+func locate(d *Daemon, section, directive, value string) (string, int) {
+	trace := d.Trace(section, directive)
+	for idx := len(trace) - 1; idx >= 0; idx-- {
+		if strings.Contains(trace[idx].Value, value) {
+			return trace[idx].File, trace[idx].Line
+		}
+	}
+
+	if len(trace) > 0 {
+		return trace[len(trace)-1].File, trace[len(trace)-1].Line
+	}
+
+	return d.Source, 0
+}
+
+// command verifies the executable of a command line, returning the severity and description of the finding, if any. Commands prefixed with "-",
+// whose failure is ignored, are reported as warnings.
+func (v Verifier) command(expander Expander, d Daemon, line string) (Severity, string) {
+	severity := SeverityError
+
+	prefixes := strings.TrimLeft(line, "@-:+!|")
+	if strings.Contains(line[:len(line)-len(prefixes)], "-") {
+		severity = SeverityWarning
+	}
+
+	words := tokens(prefixes)
+	if len(words) == 0 {
+		return SeverityError, "empty command"
+	}
+
+	executable, e := expander.Expand(d, strings.Trim(words[0], `"'`))
+	if e != nil {
+		return SeverityWarning, fmt.Sprintf("unable to verify command %s: %v", words[0], e)
+	}
+
+	candidates := []string{executable}
+	switch {
+	case strings.Contains(executable, "/") && !(path.IsAbs(executable)):
+		return severity, fmt.Sprintf("command %s is not an absolute path", executable)
+	case !(path.IsAbs(executable)):
+		candidates = make([]string, 0, len(executables))
+		for _, directory := range executables {
+			candidates = append(candidates, path.Join(directory, executable))
+		}
+	}
+
+	reason := "no such file"
+	for _, candidate := range candidates {
+		destination, _, e := v.follow(candidate)
+		if e != nil {
+			continue
+		}
+
+		info, e := fs.Stat(v.FS, relative(destination))
+		switch {
+		case e != nil:
+			continue
+		case !(info.Mode().IsRegular()):
+			reason = "not a regular file"
+		case info.Mode().Perm()&0o111 == 0:
+			reason = "permission denied"
+		default:
+			return "", ""
+		}
+	}
+
+	return severity, fmt.Sprintf("command %s is not executable: %s", executable, reason)
+}
+
+// dependency verifies that the unit of the given name exists, returning a description of the finding, if any.
+func (v Verifier) dependency(expander Expander, d Daemon, name string) string {
+	name, e := expander.Expand(d, name)
+	if e != nil {
+		return ""
+	}
+
+	resolution, e := v.Resolve(name)
+	switch {
+	case e != nil:
+		return fmt.Sprintf("unit %s not found", name)
+	case resolution.Masked:
+		return fmt.Sprintf("unit %s is masked", name)
+	}
+
+	return ""
+}
+
+// activation verifies that the unit a timer, path or socket activates by default - the service of the same name - exists, unless another unit is
+// configured, returning a description of the finding, if any.
+func (v Verifier) activation(expander Expander, d Daemon) string {
+	var name string
+	switch {
+	case d.Timer != nil && d.Timer.Unit == "", d.Path != nil && d.Path.Unit == "":
+		name = d.Prefix() + KindService.Suffix()
+	case d.Socket != nil && d.Socket.Service == "":
+		activated, e := d.Socket.Activates(d.Name)
+		if e != nil {
+			return e.Error()
+		}
+
+		name = activated
+	default:
+		return ""
+	}
+
+	if message := v.dependency(expander, d, name); message != "" {
+		return message + " (activated by default)"
+	}
+
+	return ""
+}
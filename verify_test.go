@@ -0,0 +1,233 @@
+package systemd_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestVerify(t *testing.T) {
+	root := t.TempDir()
+
+	write := func(name, content string, mode os.FileMode) {
+		p := filepath.Join(root, name)
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.WriteFile(p, []byte(content), mode); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	link := func(destination, name string) {
+		p := filepath.Join(root, name)
+		if e := os.MkdirAll(filepath.Dir(p), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.Symlink(destination, p); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	write("etc/passwd", "root:x:0:0:root:/root:/bin/sh\nagent:x:1000:1000::/home/agent:/bin/sh\n", 0o644)
+	write("etc/group", "root:x:0:\nagent:x:1000:\nadm:x:4:agent\n", 0o644)
+	write("usr/bin/agent", "#!/bin/sh\n", 0o755)
+	write("usr/bin/broken", "#!/bin/sh\n", 0o644)
+	write("usr/sbin/helper", "#!/bin/sh\n", 0o755)
+	link("/usr/bin/agent", "usr/local/bin/linked")
+
+	write("usr/lib/systemd/system/good.service", strings.Join([]string{
+		"[Unit]",
+		"Requires=network.target",
+		"Wants=missing-optional.service",
+		"After=never-checked.service",
+		"",
+		"[Service]",
+		"ExecStartPre=helper --check",
+		"ExecStart=/usr/bin/agent --serve",
+		"ExecStartPost=/usr/local/bin/linked",
+		"User=agent",
+		"Group=agent",
+		"SupplementaryGroups=adm",
+		"",
+	}, "\n"), 0o644)
+	write("usr/lib/systemd/system/network.target", "[Unit]\nDescription=Network\n", 0o644)
+
+	write("etc/systemd/system/bad.service", strings.Join([]string{
+		"[Unit]",
+		"Requires=missing.service",
+		"",
+		"[Service]",
+		"ExecStart=/usr/bin/missing",
+		"ExecStartPost=-/usr/bin/broken",
+		"User=nobody-here",
+		"SupplementaryGroups=adm wheel",
+		"",
+	}, "\n"), 0o644)
+	write("etc/systemd/system/bad.service.d/10-group.conf", "[Service]\nGroup=staff\n", 0o644)
+
+	write("etc/systemd/system/dynamic.service", "[Service]\nExecStart=/usr/bin/agent\nDynamicUser=yes\nUser=ephemeral\n", 0o644)
+	write("etc/systemd/system/orphan.timer", "[Timer]\nOnCalendar=daily\n", 0o644)
+	write("etc/systemd/system/paired.timer", "[Timer]\nOnCalendar=daily\nUnit=good.service\n", 0o644)
+	write("etc/systemd/system/confused.socket", "[Timer]\nOnCalendar=daily\n", 0o644)
+	write("usr/lib/systemd/system/worker@.service", "[Service]\nExecStart=/usr/bin/missing %i\n", 0o644)
+
+	link("/dev/null", "etc/systemd/system/masked.service")
+	link("good.service", "usr/lib/systemd/system/alias.service")
+
+	verifier := systemd.Verifier{Resolver: systemd.Resolver{FS: systemd.DirFS(root), Paths: systemd.SystemPaths()}}
+
+	t.Run("Verify-Units-Test", func(t *testing.T) {
+		units, e := verifier.Units()
+		if e != nil {
+			t.Fatalf("failed listing units: %v", e)
+		}
+
+		expected := []string{"bad.service", "confused.socket", "dynamic.service", "good.service", "network.target", "orphan.timer", "paired.timer"}
+		if !(reflect.DeepEqual(units, expected)) {
+			t.Errorf("expected units %v, got %v", expected, units)
+		}
+	})
+
+	t.Run("Verify-Clean-Test", func(t *testing.T) {
+		diagnostics, e := verifier.Verify("good.service", "dynamic.service", "paired.timer")
+		if e != nil {
+			t.Fatalf("failed verifying units: %v", e)
+		}
+
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Severity == systemd.SeverityError {
+				t.Errorf("unexpected error diagnostic: %s", diagnostic)
+			}
+		}
+
+		if len(diagnostics) != 1 || diagnostics[0].Directive != "Wants" || diagnostics[0].Value != "missing-optional.service" {
+			t.Errorf("expected a single Wants= warning, got %v", diagnostics)
+		}
+	})
+
+	t.Run("Verify-Diagnostics-Test", func(t *testing.T) {
+		diagnostics, e := verifier.Verify("bad.service")
+		if e != nil {
+			t.Fatalf("failed verifying unit: %v", e)
+		}
+
+		type finding struct {
+			Severity  systemd.Severity
+			Category  systemd.Category
+			Directive string
+			Value     string
+		}
+
+		found := make([]finding, 0, len(diagnostics))
+		for _, diagnostic := range diagnostics {
+			found = append(found, finding{diagnostic.Severity, diagnostic.Category, diagnostic.Directive, diagnostic.Value})
+		}
+
+		expected := []finding{
+			{systemd.SeverityError, systemd.CategoryDependency, "Requires", "missing.service"},
+			{systemd.SeverityError, systemd.CategoryExecutable, "ExecStart", "/usr/bin/missing"},
+			{systemd.SeverityWarning, systemd.CategoryExecutable, "ExecStartPost", "-/usr/bin/broken"},
+			{systemd.SeverityError, systemd.CategoryUser, "User", "nobody-here"},
+			{systemd.SeverityError, systemd.CategoryGroup, "Group", "staff"},
+			{systemd.SeverityError, systemd.CategoryGroup, "SupplementaryGroups", "wheel"},
+		}
+
+		if !(reflect.DeepEqual(found, expected)) {
+			t.Errorf("expected diagnostics:\n%v\ngot:\n%v", expected, found)
+		}
+	})
+
+	t.Run("Verify-Location-Test", func(t *testing.T) {
+		diagnostics, e := verifier.Verify("bad.service")
+		if e != nil {
+			t.Fatalf("failed verifying unit: %v", e)
+		}
+
+		locations := make(map[string]string)
+		for _, diagnostic := range diagnostics {
+			locations[diagnostic.Directive] = diagnostic.String()
+		}
+
+		if v := locations["ExecStart"]; v != "/etc/systemd/system/bad.service:5: [Service] ExecStart=: command /usr/bin/missing is not executable: no such file" {
+			t.Errorf("unexpected ExecStart= diagnostic: %s", v)
+		}
+
+		if v := locations["ExecStartPost"]; !(strings.HasSuffix(v, "permission denied")) {
+			t.Errorf("expected ExecStartPost= to be reported as not executable: %s", v)
+		}
+
+		if v := locations["Group"]; !(strings.HasPrefix(v, "/etc/systemd/system/bad.service.d/10-group.conf:2: ")) {
+			t.Errorf("expected Group= to be located in the drop-in: %s", v)
+		}
+	})
+
+	t.Run("Verify-Activation-Test", func(t *testing.T) {
+		diagnostics, e := verifier.Verify("orphan.timer")
+		if e != nil {
+			t.Fatalf("failed verifying unit: %v", e)
+		}
+
+		if len(diagnostics) != 1 || diagnostics[0].Category != systemd.CategoryDependency || !(strings.Contains(diagnostics[0].Message, "orphan.service")) {
+			t.Errorf("expected the implicitly activated service to be missing, got %v", diagnostics)
+		}
+	})
+
+	t.Run("Verify-Type-Test", func(t *testing.T) {
+		diagnostics, e := verifier.Verify("confused.socket")
+		if e != nil {
+			t.Fatalf("failed verifying unit: %v", e)
+		}
+
+		if len(diagnostics) != 1 || diagnostics[0].Category != systemd.CategoryType || diagnostics[0].Severity != systemd.SeverityError {
+			t.Errorf("expected a single type mismatch, got %v", diagnostics)
+		}
+	})
+
+	t.Run("Verify-Load-Test", func(t *testing.T) {
+		diagnostics, e := verifier.Verify("masked.service", "absent.service")
+		if e != nil {
+			t.Fatalf("failed verifying units: %v", e)
+		}
+
+		if len(diagnostics) != 2 {
+			t.Fatalf("expected 2 diagnostics, got %v", diagnostics)
+		}
+
+		if diagnostics[0].Severity != systemd.SeverityWarning || diagnostics[0].Category != systemd.CategoryLoad {
+			t.Errorf("expected a masked unit warning, got %v", diagnostics[0])
+		}
+
+		if diagnostics[1].Severity != systemd.SeverityError || diagnostics[1].Category != systemd.CategoryLoad {
+			t.Errorf("expected a load error, got %v", diagnostics[1])
+		}
+	})
+
+	t.Run("Verify-Root-Test", func(t *testing.T) {
+		diagnostics, e := systemd.Verify(root)
+		if e != nil {
+			t.Fatalf("failed verifying root: %v", e)
+		}
+
+		units := make(map[string]bool)
+		for _, diagnostic := range diagnostics {
+			units[diagnostic.Unit] = true
+		}
+
+		for _, unit := range []string{"bad.service", "confused.socket", "orphan.timer"} {
+			if !(units[unit]) {
+				t.Errorf("expected diagnostics for %s", unit)
+			}
+		}
+
+		if units["masked.service"] || units["alias.service"] || units["worker@.service"] {
+			t.Errorf("expected masked units, aliases and templates to be skipped: %v", diagnostics)
+		}
+	})
+}
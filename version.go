@@ -0,0 +1,263 @@
+package systemd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Option configures the behavior of [Marshal] and [Unmarshal].
+type Option func(*options)
+
+type options struct {
+	target int    // The systemd version to render or validate units for; 0 for the latest.
+	legacy bool   // Whether the target host uses the legacy (v1) cgroup hierarchy.
+	name   string // The unit's file name, e.g. "srv-data.mount"; an empty string skips unit name checks.
+}
+
+func configure(settings ...Option) options {
+	var o options
+	for _, setting := range settings {
+		if setting != nil {
+			setting(&o)
+		}
+	}
+
+	return o
+}
+
+// TargetVersion restricts a unit to the directives and values understood by the given systemd release (e.g. 239 for RHEL 8).
+//
+// Directives or values newer than the target are rewritten where a safe equivalent exists (e.g. ReadWritePaths= -> ReadWriteDirectories=). All
+// others, including values whose older equivalent behaves differently (e.g. Type=exec, which reports start failures Type=simple doesn't), are
+// reported through a [CompatibilityError].
+func TargetVersion(version int) Option {
+	return func(o *options) {
+		o.target = version
+	}
+}
+
+// LegacyHierarchy indicates the target host mounts the legacy (v1) cgroup hierarchy, where unified-hierarchy resource-control directives such as
+// MemoryMax= and CPUWeight= are rewritten to their legacy counterparts (MemoryLimit=, CPUShares=).
+func LegacyHierarchy() Option {
+	return func(o *options) {
+		o.legacy = true
+	}
+}
+
+// UnitName specifies the unit's file name (e.g. "srv-data.mount"), enabling the checks systemd applies to unit names when loading a unit: mount
+// and automount units must be named after their escaped Where= path, and swap units after their escaped What= path.
+func UnitName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// Incompatibility represents a single directive or value that isn't supported by a target systemd version.
+type Incompatibility struct {
+	Section   string `json:"Section" yaml:"Section"`                     // Specifies the section of the directive, e.g. "Service".
+	Directive string `json:"Directive" yaml:"Directive"`                 // Specifies the directive's key, e.g. "ProtectProc".
+	Value     string `json:"Value" yaml:"Value"`                         // Specifies the directive's value.
+	Since     int    `json:"Since" yaml:"Since"`                         // Specifies the systemd version that introduced the directive or value; 0 for unified-hierarchy only directives.
+	Rewrite   string `json:"Rewrite,omitempty" yaml:"Rewrite,omitempty"` // Specifies the "Key=Value" assignment the directive was rewritten to, or an empty string if no safe equivalent exists.
+}
+
+func (i Incompatibility) Error() string {
+	var reason = fmt.Sprintf("requires systemd %d", i.Since)
+	if i.Since == 0 {
+		reason = "requires the unified cgroup hierarchy"
+	}
+
+	if i.Rewrite != "" {
+		return fmt.Sprintf("[%s] %s=%s %s (rewritten as %s)", i.Section, i.Directive, i.Value, reason, i.Rewrite)
+	}
+
+	return fmt.Sprintf("[%s] %s=%s %s", i.Section, i.Directive, i.Value, reason)
+}
+
+// CompatibilityError is returned when a unit contains directives or values without a safe equivalent for the target systemd version.
+type CompatibilityError struct {
+	Target            int               // Specifies the target systemd version.
+	Incompatibilities []Incompatibility // Specifies the unresolved incompatibilities.
+}
+
+func (c *CompatibilityError) Error() string {
+	partials := make([]string, 0, len(c.Incompatibilities))
+	for _, incompatibility := range c.Incompatibilities {
+		partials = append(partials, incompatibility.Error())
+	}
+
+	return fmt.Sprintf("unit incompatible with systemd %d: %s", c.Target, strings.Join(partials, "; "))
+}
+
+// substitution represents a directive, or a specific directive value, that can be rewritten to an equivalent understood by older systemd releases.
+type substitution struct {
+	Section     string // The section of the directive; an empty string matches any section.
+	Directive   string
+	Value       string // The specific value being rewritten; an empty string matches a directive regardless of its value.
+	Since       int    // The version the directive or value was introduced in.
+	Unified     bool   // Whether the directive only applies to the unified (v2) cgroup hierarchy.
+	Replacement string // The directive to rewrite to; an empty string keeps the original directive.
+
+	convert func(string) (string, bool)
+}
+
+// substitutions represents the known rewrites, in order of precedence. Weights are converted as systemd converts them between hierarchies, e.g.
+// cgroup_weight_io_to_blkio() maps the default IOWeight=100 to the default BlockIOWeight=500.
+var substitutions = []substitution{
+	{Section: "Service", Directive: "Type", Value: "notify-reload", Since: 253, convert: constant("notify")},
+	{Directive: "MemoryMax", Since: 231, Unified: true, Replacement: "MemoryLimit"},
+	{Directive: "CPUWeight", Since: 232, Unified: true, Replacement: "CPUShares", convert: scale(1024, 100, 2, 262144)},
+	{Directive: "StartupCPUWeight", Since: 232, Unified: true, Replacement: "StartupCPUShares", convert: scale(1024, 100, 2, 262144)},
+	{Directive: "IOAccounting", Since: 230, Unified: true, Replacement: "BlockIOAccounting"},
+	{Directive: "IOWeight", Since: 230, Unified: true, Replacement: "BlockIOWeight", convert: scale(500, 100, 10, 1000)},
+	{Directive: "StartupIOWeight", Since: 230, Unified: true, Replacement: "StartupBlockIOWeight", convert: scale(500, 100, 10, 1000)},
+	{Directive: "IOReadBandwidthMax", Since: 230, Unified: true, Replacement: "BlockIOReadBandwidth"},
+	{Directive: "IOWriteBandwidthMax", Since: 230, Unified: true, Replacement: "BlockIOWriteBandwidth"},
+	{Directive: "ReadWritePaths", Since: 231, Replacement: "ReadWriteDirectories"},
+	{Directive: "ReadOnlyPaths", Since: 231, Replacement: "ReadOnlyDirectories"},
+	{Directive: "InaccessiblePaths", Since: 231, Replacement: "InaccessibleDirectories"},
+}
+
+// values represents directive values introduced after the directive itself, and without a safe equivalent.
+var values = []substitution{
+	{Section: "Service", Directive: "Type", Value: "exec", Since: 240},
+	{Directive: "ProtectSystem", Value: "strict", Since: 232},
+	{Directive: "ProtectHome", Value: "tmpfs", Since: 242},
+	{Directive: "Restart", Value: "on-abnormal", Since: 215},
+	{Directive: "NotifyAccess", Value: "exec", Since: 232},
+	{Directive: "KillMode", Value: "mixed", Since: 209},
+	{Directive: "PrivateUsers", Value: "self", Since: 255},
+	{Directive: "PrivateUsers", Value: "identity", Since: 255},
+}
+
+// prefixes represents directive value prefixes introduced after the directive itself, such as StandardOutput=file:/var/log/example.log.
+var prefixes = []substitution{
+	{Directive: "StandardOutput", Value: "file:", Since: 236},
+	{Directive: "StandardOutput", Value: "append:", Since: 240},
+	{Directive: "StandardOutput", Value: "truncate:", Since: 248},
+	{Directive: "StandardError", Value: "file:", Since: 236},
+	{Directive: "StandardError", Value: "append:", Since: 240},
+	{Directive: "StandardError", Value: "truncate:", Since: 248},
+}
+
+// constant returns a converter that always produces the given value.
+func constant(v string) func(string) (string, bool) {
+	return func(string) (string, bool) {
+		return v, true
+	}
+}
+
+// scale returns a converter that multiplies an integer value by numerator/denominator, clamped to [minimum, maximum].
+func scale(numerator, denominator, minimum, maximum int) func(string) (string, bool) {
+	return func(v string) (string, bool) {
+		n, e := strconv.Atoi(strings.TrimSpace(v))
+		if e != nil {
+			return "", false
+		}
+
+		n = n * numerator / denominator
+		n = max(minimum, min(maximum, n))
+
+		return strconv.Itoa(n), true
+	}
+}
+
+// applies reports whether the substitution is required for the given entry and options.
+func (s substitution) applies(section string, assignment entry, o options) bool {
+	if (s.Section != "" && s.Section != section) || s.Directive != assignment.Key || (s.Value != "" && s.Value != assignment.Value) {
+		return false
+	}
+
+	return (o.target > 0 && o.target < s.Since) || (o.legacy && s.Unified)
+}
+
+// since returns the version reported for an incompatibility caused by the substitution.
+func (s substitution) since(o options) int {
+	if o.target > 0 && o.target < s.Since {
+		return s.Since
+	}
+
+	return 0
+}
+
+// downgrade rewrites the document's directives for the configured target, returning both the applied rewrites and the unresolved
+// incompatibilities. The document is modified in place; unresolved directives are kept as-is.
+func downgrade(file *document, o options) (rewritten []Incompatibility, unresolved []Incompatibility) {
+	if o.target <= 0 && !(o.legacy) {
+		return nil, nil
+	}
+
+	for _, g := range file.Groups {
+	assignments:
+		for idx, assignment := range g.Entries {
+			for _, s := range substitutions {
+				if !(s.applies(g.Name, assignment, o)) {
+					continue
+				}
+
+				incompatibility := Incompatibility{Section: g.Name, Directive: assignment.Key, Value: assignment.Value, Since: s.since(o)}
+
+				key, value := assignment.Key, assignment.Value
+				if s.Replacement != "" {
+					key = s.Replacement
+				}
+
+				if s.convert != nil {
+					v, ok := s.convert(value)
+					if !(ok) {
+						unresolved = append(unresolved, incompatibility)
+						continue assignments
+					}
+
+					value = v
+				}
+
+				g.Entries[idx].Key, g.Entries[idx].Value = key, value
+
+				incompatibility.Rewrite = key + "=" + value
+				rewritten = append(rewritten, incompatibility)
+
+				continue assignments
+			}
+
+			if o.target <= 0 {
+				continue
+			}
+
+			if directive, ok := Lookup(g.Name, assignment.Key); ok && directive.Since > o.target {
+				unresolved = append(unresolved, Incompatibility{Section: g.Name, Directive: assignment.Key, Value: assignment.Value, Since: directive.Since})
+				continue
+			}
+
+			for _, s := range values {
+				if (s.Section == "" || s.Section == g.Name) && s.Directive == assignment.Key && s.Value == assignment.Value && o.target < s.Since {
+					unresolved = append(unresolved, Incompatibility{Section: g.Name, Directive: assignment.Key, Value: assignment.Value, Since: s.Since})
+					continue assignments
+				}
+			}
+
+			for _, s := range prefixes {
+				if s.Directive == assignment.Key && strings.HasPrefix(assignment.Value, s.Value) && o.target < s.Since {
+					unresolved = append(unresolved, Incompatibility{Section: g.Name, Directive: assignment.Key, Value: assignment.Value, Since: s.Since})
+					continue assignments
+				}
+			}
+		}
+	}
+
+	return rewritten, unresolved
+}
+
+// Check reports every directive or value of the daemon that isn't supported by the configured target (see [TargetVersion] and
+// [LegacyHierarchy]). Incompatibilities with a safe equivalent specify the assignment [Marshal] rewrites them to.
+func Check(systemd Daemon, settings ...Option) ([]Incompatibility, error) {
+	file, e := systemd.document()
+	if e != nil {
+		return nil, e
+	}
+
+	rewritten, unresolved := downgrade(file, configure(settings...))
+
+	return append(rewritten, unresolved...), nil
+}
@@ -0,0 +1,148 @@
+package systemd_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/poly-gun/systemd"
+)
+
+func TestTargetVersion(t *testing.T) {
+	daemon := systemd.Daemon{
+		Unit: systemd.Unit{
+			Description: "Versioned Daemon",
+		},
+		Service: systemd.Service{
+			Type:      "notify-reload",
+			ExecStart: []string{"/usr/bin/example-agent"},
+			Exec: systemd.Exec{
+				ProtectHome: "tmpfs",
+			},
+			ResourceControl: systemd.ResourceControl{
+				MemoryMax: "1G",
+				CPUWeight: "50",
+			},
+		},
+	}
+
+	t.Run("Marshal-Latest-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon)
+		if e != nil {
+			t.Fatalf("failed marshalling daemon: %v", e)
+		}
+
+		if !(strings.Contains(string(content), "Type=notify-reload\n")) {
+			t.Errorf("unexpected marshalled output:\n%s", string(content))
+		}
+	})
+
+	t.Run("Marshal-Target-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon, systemd.TargetVersion(239))
+
+		var exception *systemd.CompatibilityError
+		if !(errors.As(e, &exception)) {
+			t.Fatalf("expected a compatibility error, received: %v", e)
+		}
+
+		if len(exception.Incompatibilities) != 1 || exception.Incompatibilities[0].Directive != "ProtectHome" || exception.Incompatibilities[0].Since != 242 {
+			t.Errorf("unexpected incompatibilities: %+v", exception.Incompatibilities)
+		}
+
+		if !(strings.Contains(string(content), "Type=notify\n")) {
+			t.Errorf("expected Type=notify-reload to be rewritten:\n%s", string(content))
+		}
+
+		if !(strings.Contains(string(content), "MemoryMax=1G\n")) {
+			t.Errorf("expected MemoryMax= to be kept on a unified hierarchy:\n%s", string(content))
+		}
+	})
+
+	t.Run("Marshal-Legacy-Hierarchy-Test", func(t *testing.T) {
+		content, e := systemd.Marshal(daemon, systemd.TargetVersion(239), systemd.LegacyHierarchy())
+		if e == nil {
+			t.Fatalf("expected a compatibility error")
+		}
+
+		for _, expectation := range []string{"MemoryLimit=1G\n", "CPUShares=512\n"} {
+			if !(strings.Contains(string(content), expectation)) {
+				t.Errorf("expected %q in marshalled output:\n%s", expectation, string(content))
+			}
+		}
+
+		if strings.Contains(string(content), "MemoryMax=") {
+			t.Errorf("unexpected MemoryMax= in marshalled output:\n%s", string(content))
+		}
+	})
+
+	t.Run("Check-Test", func(t *testing.T) {
+		incompatibilities, e := systemd.Check(daemon, systemd.TargetVersion(239))
+		if e != nil {
+			t.Fatalf("failed checking daemon: %v", e)
+		}
+
+		var rewritten int
+		for _, incompatibility := range incompatibilities {
+			if incompatibility.Rewrite != "" {
+				rewritten++
+			}
+		}
+
+		if len(incompatibilities) != 2 || rewritten != 1 {
+			t.Errorf("unexpected incompatibilities: %+v", incompatibilities)
+		}
+	})
+
+	t.Run("Unmarshal-Target-Test", func(t *testing.T) {
+		content := []byte("[Unit]\nDescription=Versioned\n\n[Service]\nType=exec\nExecStart=/usr/bin/example-agent\nProtectProc=invisible\n")
+
+		instance, e := systemd.Unmarshal(content, systemd.TargetVersion(239))
+
+		var exception *systemd.CompatibilityError
+		if !(errors.As(e, &exception)) {
+			t.Fatalf("expected a compatibility error, received: %v", e)
+		}
+
+		if len(exception.Incompatibilities) != 2 || exception.Incompatibilities[0].Directive != "Type" || exception.Incompatibilities[1].Directive != "ProtectProc" {
+			t.Errorf("unexpected incompatibilities: %+v", exception.Incompatibilities)
+		}
+
+		// Type=simple doesn't report exec failures as start failures, and as such, isn't a safe equivalent of Type=exec.
+		if instance == nil || instance.Service.Type != "exec" || len(instance.Rewrites) != 0 {
+			t.Errorf("expected Type=exec to be kept as-is: %+v", instance)
+		}
+	})
+
+	t.Run("Unmarshal-Rewrites-Test", func(t *testing.T) {
+		content := []byte("[Unit]\nDescription=Versioned\n\n[Service]\nExecStart=/usr/bin/example-agent\nMemoryMax=1G\n")
+
+		instance, e := systemd.Unmarshal(content, systemd.TargetVersion(239), systemd.LegacyHierarchy())
+		if e != nil {
+			t.Fatalf("failed unmarshalling daemon: %v", e)
+		}
+
+		if instance.Service.MemoryMax != "" || instance.Service.MemoryLimit != "1G" {
+			t.Errorf("expected MemoryMax= to be rewritten: %+v", instance.Service)
+		}
+
+		if len(instance.Rewrites) != 1 || instance.Rewrites[0].Directive != "MemoryMax" || instance.Rewrites[0].Rewrite != "MemoryLimit=1G" {
+			t.Errorf("unexpected rewrites: %+v", instance.Rewrites)
+		}
+	})
+
+	t.Run("Marshal-IO-Weight-Test", func(t *testing.T) {
+		slice := systemd.Daemon{
+			Name:  "batch.slice",
+			Slice: &systemd.Slice{ResourceControl: systemd.ResourceControl{IOWeight: "100"}},
+		}
+
+		content, e := systemd.Marshal(slice, systemd.LegacyHierarchy())
+		if e != nil {
+			t.Fatalf("failed marshalling slice: %v", e)
+		}
+
+		if !(strings.Contains(string(content), "BlockIOWeight=500\n")) {
+			t.Errorf("expected the default IOWeight=100 to map to the default BlockIOWeight=500:\n%s", string(content))
+		}
+	})
+}